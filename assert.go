@@ -0,0 +1,87 @@
+package deepequal
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AssertEqual checks if want and got are equal and reports an error with their
+// difference otherwise. Nothing is logged on a match.
+//
+// Args are options, which tune the comparison and the output just like for
+// SideBySideWith, mixed with an optional message: a single value or a format
+// string with its arguments.
+//
+//	deepequal.AssertEqual(t, want, got, deepequal.Unified(), "user %d", id)
+func AssertEqual[T any](p TestPrinter, want, got T, args ...any) bool {
+	p.Helper()
+
+	if msg, ok := mismatch(want, got, args); !ok {
+		p.Error(msg)
+		return false
+	}
+
+	return true
+}
+
+// RequireEqual does the same as AssertEqual but stops the test with Fatal on a mismatch.
+func RequireEqual[T any](p TestPrinter, want, got T, args ...any) {
+	p.Helper()
+
+	if msg, ok := mismatch(want, got, args); !ok {
+		p.Fatal(msg)
+	}
+}
+
+// mismatch compares values and returns a failure message with their difference if they differ.
+func mismatch(want, got any, args []any) (string, bool) {
+	var opts []Option
+	var msgAndArgs []any
+	for _, arg := range args {
+		if opt, ok := arg.(Option); ok {
+			opts = append(opts, opt)
+			continue
+		}
+
+		msgAndArgs = append(msgAndArgs, arg)
+	}
+
+	lv := reflect.ValueOf(want)
+	rv := reflect.ValueOf(got)
+	o := newOptions(opts)
+	if equalValues(lv, rv, o, o.pathFor(lv)) {
+		return "", true
+	}
+
+	res := "mismatched expected and actual values"
+	if msg := formatMessage(msgAndArgs); msg != "" {
+		res += ": " + msg
+	}
+	res += renderDiff(lv, rv, o)
+
+	if o.actualLiteral {
+		res += "\nactual value as Go literal:\n" + newLiteralWriter().literal(rv)
+	}
+
+	return res, false
+}
+
+// formatMessage formats an optional assertion message.
+func formatMessage(msgAndArgs []any) string {
+	switch len(msgAndArgs) {
+	case 0:
+		return ""
+	case 1:
+		if msg, ok := msgAndArgs[0].(string); ok {
+			return msg
+		}
+
+		return fmt.Sprintf("%+v", msgAndArgs[0])
+	}
+
+	if format, ok := msgAndArgs[0].(string); ok {
+		return fmt.Sprintf(format, msgAndArgs[1:]...)
+	}
+
+	return fmt.Sprint(msgAndArgs...)
+}
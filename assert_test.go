@@ -0,0 +1,92 @@
+package deepequal_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirkon/deepequal"
+)
+
+func TestAssertEqual(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+
+	type sample struct {
+		Name string
+		Tags []string
+	}
+
+	var rec recordingTesting
+	if !deepequal.AssertEqual(&rec, sample{Name: "a"}, sample{Name: "a"}) {
+		t.Error("equal values must match")
+	}
+	if rec.logs.Len() != 0 || rec.errors.Len() != 0 {
+		t.Errorf("nothing must be printed on a match, got %q and %q", rec.logs.String(), rec.errors.String())
+	}
+
+	if deepequal.AssertEqual(
+		&rec,
+		sample{Name: "a", Tags: []string{"x"}},
+		sample{Name: "b", Tags: []string{"x"}},
+		deepequal.Colors(deepequal.ColorNever),
+		"sample %d",
+		12,
+	) {
+		t.Error("different values must not match")
+	}
+	if rec.fatal {
+		t.Error("AssertEqual must not stop the test")
+	}
+	expected := `mismatched expected and actual values: sample 12
+  Expected                  Actual
+  deepequal_test.sample{    deepequal_test.sample{
+-   Name: "a",            +   Name: "b",
+    Tags: []string{           Tags: []string{
+      "x",                      "x",
+    },                        },
+  }                         }
+`
+	if rec.errors.String() != expected {
+		t.Errorf("unexpected output:\n%s", rec.errors.String())
+	}
+
+	rec = recordingTesting{}
+	deepequal.RequireEqual(&rec, sample{Name: "a"}, sample{Name: "a", Tags: []string{}}, deepequal.EquateEmpty())
+	if rec.fatal || rec.errors.Len() != 0 {
+		t.Errorf("options must be applied, got %q", rec.errors.String())
+	}
+
+	deepequal.RequireEqual(&rec, 1, 2, deepequal.Colors(deepequal.ColorNever), []int{1, 2})
+	if !rec.fatal {
+		t.Error("RequireEqual must stop the test on a mismatch")
+	}
+	if !strings.HasPrefix(rec.errors.String(), "mismatched expected and actual values: [1 2]\n") {
+		t.Errorf("unexpected output:\n%s", rec.errors.String())
+	}
+}
+
+func TestAssertEqualFuncsAndChans(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+
+	type handler struct {
+		Name string
+		Call func()
+		Done chan struct{}
+	}
+
+	done := make(chan struct{})
+	for _, mode := range []deepequal.Option{deepequal.Colors(deepequal.ColorNever), deepequal.Unified()} {
+		var rec recordingTesting
+		if !deepequal.AssertEqual(&rec, handler{Name: "a", Done: done}, handler{Name: "a", Done: done}, mode) {
+			t.Errorf("equal values must match: %s", rec.errors.String())
+		}
+
+		if deepequal.AssertEqual(&rec, handler{Name: "a", Done: done}, handler{Name: "a", Call: func() {}}, mode, deepequal.Colors(deepequal.ColorNever)) {
+			t.Error("different values must not match")
+		}
+		for _, part := range []string{"Call: (func())(nil),", "Call: (func())(0x", "Done: (chan struct {})(0x", "Done: (chan struct {})(nil),"} {
+			if !strings.Contains(rec.errors.String(), part) {
+				t.Errorf("%q is missing in the output\n%s", part, rec.errors.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package deepequal
+
+import (
+	"fmt"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirkon/deepequal/internal/diff"
+)
+
+// hexdumpWidth is the number of bytes in a hexdump line.
+const hexdumpWidth = 8
+
+// isBytes checks if the type is a slice or an array of bytes.
+func isBytes(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() == reflect.Uint8
+}
+
+// bytesOf returns a content of a slice or an array of bytes.
+func bytesOf(v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return v.Bytes()
+	}
+
+	res := make([]byte, v.Len())
+	for i := range res {
+		res[i] = byte(v.Index(i).Uint())
+	}
+
+	return res
+}
+
+// isText checks if bytes are better to be shown as a text.
+func isText(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bytesDifference builds a difference of bytes. Texts are diffed
+// as strings, other data is diffed bytewise.
+func bytesDifference(ld, rd []byte) diff.Diff {
+	if isText(ld) && isText(rd) {
+		return textDifference(string(ld), string(rd))
+	}
+
+	left, right := sequenceDiff(len(ld), len(rd), func(i, j int) bool {
+		return ld[i] == rd[j]
+	})
+
+	return &diff.Indices{
+		Left:  missingIndices(left),
+		Right: missingIndices(right),
+	}
+}
+
+// printBytes prints a slice or an array of bytes either as a text or as a hexdump.
+func (p *printer) printBytes(offset string, v reflect.Value, d diff.Diff) {
+	data := bytesOf(v)
+
+	switch vv := d.(type) {
+	case *diff.Text:
+		p.printText(offset, v.Type(), vv)
+	case *diff.Indices:
+		p.printHexdump(offset, v.Type(), data, p.sliceDiff(d))
+	default:
+		if isText(data) {
+			_, _ = fmt.Fprintf(p.buf, "%s(%q)", v.Type().String(), data)
+			return
+		}
+
+		p.printHexdump(offset, v.Type(), data, nil)
+	}
+}
+
+// printHexdump prints bytes in a hexdump format with offsets and differing bytes highlighted.
+func (p *printer) printHexdump(offset string, t reflect.Type, data []byte, ds map[int]diff.Diff) {
+	_, _ = fmt.Fprintf(p.buf, "%s{\n\r", t.String())
+
+	for start := 0; start < len(data); start += hexdumpWidth {
+		end := start + hexdumpWidth
+		if end > len(data) {
+			end = len(data)
+		}
+
+		p.buf.WriteString(offset)
+		_, _ = fmt.Fprintf(p.buf, "  %08x  ", start)
+		for i := start; i < start+hexdumpWidth; i++ {
+			if i >= end {
+				p.buf.WriteString("   ")
+				continue
+			}
+
+			p.printSegment(fmt.Sprintf("%02x", data[i]), ds[i] != nil)
+			p.buf.WriteByte(' ')
+		}
+
+		p.buf.WriteString(" |")
+		for i := start; i < end; i++ {
+			c := "."
+			if data[i] >= 0x20 && data[i] < 0x7f {
+				c = string(rune(data[i]))
+			}
+			p.printSegment(c, ds[i] != nil)
+		}
+		p.buf.WriteString("|\n\r")
+	}
+
+	p.buf.WriteString(offset)
+	p.buf.WriteString("}")
+}
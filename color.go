@@ -0,0 +1,51 @@
+package deepequal
+
+import (
+	"os"
+)
+
+// ColorMode defines whether differences are highlighted with ANSI colors.
+type ColorMode int
+
+const (
+	// ColorAuto uses colors when the standard output is a terminal
+	// and NO_COLOR environment variable is not set.
+	ColorAuto ColorMode = iota
+	// ColorAlways always uses colors.
+	ColorAlways
+	// ColorNever never uses colors, differing lines are marked with -/+ gutters instead.
+	ColorNever
+)
+
+// Colors sets a color mode of the output. ColorAuto is the default.
+func Colors(mode ColorMode) Option {
+	return func(o *options) {
+		o.colors = mode
+	}
+}
+
+// colored checks if the output must be colored.
+func (o *options) colored() bool {
+	switch o.colors {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	// See https://no-color.org.
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return isTerminal(os.Stdout)
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,69 @@
+package deepequal
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// comparer checks if two values of the same type are equal.
+type comparer func(x, y reflect.Value) bool
+
+// globalComparers holds map[reflect.Type]comparer which is copied on every registration.
+// Registrations are rare, lookups are not.
+var globalComparers = struct {
+	sync.Mutex
+	fns atomic.Value
+}{}
+
+// RegisterComparer registers a comparer for values of type T to be used by every comparison.
+// Registered comparer replaces the previous one registered for the same type.
+func RegisterComparer[T any](cmp func(a, b T) bool) {
+	globalComparers.Lock()
+	defer globalComparers.Unlock()
+
+	prev, _ := globalComparers.fns.Load().(map[reflect.Type]comparer)
+	fns := make(map[reflect.Type]comparer, len(prev)+1)
+	for t, fn := range prev {
+		fns[t] = fn
+	}
+	fns[typeOf[T]()] = newComparer(cmp)
+
+	globalComparers.fns.Store(fns)
+}
+
+// WithComparer sets a comparer for values of type T for a particular comparison.
+// It takes precedence over the one set with RegisterComparer.
+func WithComparer[T any](cmp func(a, b T) bool) Option {
+	return func(o *options) {
+		if o.comparers == nil {
+			o.comparers = map[reflect.Type]comparer{}
+		}
+
+		o.comparers[typeOf[T]()] = newComparer(cmp)
+	}
+}
+
+// comparer returns a comparer for the given type if there is any.
+func (o *options) comparer(t reflect.Type) comparer {
+	if cmp, ok := o.comparers[t]; ok {
+		return cmp
+	}
+
+	fns, _ := globalComparers.fns.Load().(map[reflect.Type]comparer)
+	return fns[t]
+}
+
+func newComparer[T any](cmp func(a, b T) bool) comparer {
+	return func(x, y reflect.Value) bool {
+		// Interface types may hold nils, thus no checks here.
+		xv, _ := x.Interface().(T)
+		yv, _ := y.Interface().(T)
+
+		return cmp(xv, yv)
+	}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -12,30 +12,45 @@ import (
 // protoc-gen-go. This is not a drop-in replacement for reflect.DeepEqual as some functionality
 // is missing. Should be enough in most cases though.
 func Equal(x, y any) bool {
-	if x == nil && y == nil {
-		return x == y
-	}
+	return EqualWith(x, y)
+}
 
+// EqualWith does the same as Equal but with an ability to tune comparison with options.
+func EqualWith(x, y any, opts ...Option) bool {
 	xv := reflect.ValueOf(x)
-	yv := reflect.ValueOf(y)
+	o := newOptions(opts)
+	return equalValues(xv, reflect.ValueOf(y), o, o.pathFor(xv))
+}
 
-	if !(xv.IsValid() && yv.IsValid()) && (xv.IsValid() || yv.IsValid()) {
-		return false
+// equalValues is EqualWith for already reflected values.
+func equalValues(xv, yv reflect.Value, opts *options, path valuePath) bool {
+	if !xv.IsValid() || !yv.IsValid() {
+		return xv.IsValid() == yv.IsValid()
 	}
 
 	if xv.Type() != yv.Type() {
 		return false
 	}
 
-	return deepEqual(xv, yv, map[visit]bool{})
+	return deepEqual(xv, yv, map[visit]bool{}, opts, path)
 }
 
-func deepEqual(x reflect.Value, y reflect.Value, visited map[visit]bool) bool {
+func deepEqual(x reflect.Value, y reflect.Value, visited map[visit]bool, opts *options, path valuePath) bool {
 	if !x.IsValid() || !y.IsValid() {
 		return x.IsValid() == y.IsValid()
 	}
 
-	if pbx, ok := getProtoMessage(x.Interface()); ok {
+	if cmp := opts.comparer(x.Type()); cmp != nil {
+		return cmp(x, y)
+	}
+
+	if m := opts.equalMethod(x.Type()); m != nil {
+		return m.call(x, y)
+	}
+
+	if !x.Type().Implements(protoMessageType) {
+		// Spare the costly Interface call in the most cases.
+	} else if pbx, ok := getProtoMessage(x.Interface()); ok {
 		if pby, ok := getProtoMessage(y.Interface()); ok {
 			switch {
 			case pbx == nil && pby == nil:
@@ -46,6 +61,10 @@ func deepEqual(x reflect.Value, y reflect.Value, visited map[visit]bool) bool {
 				return false
 			}
 
+			if opts.protoWalk() {
+				return protoEqual(pbx.ProtoReflect(), pby.ProtoReflect(), opts, path)
+			}
+
 			return proto.Equal(pbx, pby)
 		}
 	}
@@ -87,12 +106,15 @@ func deepEqual(x reflect.Value, y reflect.Value, visited map[visit]bool) bool {
 	switch x.Kind() {
 	case reflect.Array:
 		for i := 0; i < x.Len(); i++ {
-			if !deepEqual(x.Index(i), x.Index(i), visited) {
+			if !deepEqual(x.Index(i), y.Index(i), visited, opts, path.index(i)) {
 				return false
 			}
 		}
 		return true
 	case reflect.Slice:
+		if opts.equateEmpty && x.Len() == 0 && y.Len() == 0 {
+			return true
+		}
 		if x.IsNil() != y.IsNil() {
 			return false
 		}
@@ -102,12 +124,19 @@ func deepEqual(x reflect.Value, y reflect.Value, visited map[visit]bool) bool {
 		if x.UnsafePointer() == y.UnsafePointer() {
 			return true
 		}
+		if opts.unordered(x.Type().Elem(), path) {
+			left, _ := matchMultisets(x.Len(), y.Len(), func(i, j int) bool {
+				// Mismatches are expected here, so every pair needs its own visited set.
+				return deepEqual(x.Index(i), y.Index(j), map[visit]bool{}, opts, path.index(i))
+			})
+			return len(left) == 0
+		}
 		// Special case for []byte, which is common.
 		if x.Type().Elem().Kind() == reflect.Uint8 {
 			return bytes.Equal(x.Bytes(), y.Bytes())
 		}
 		for i := 0; i < x.Len(); i++ {
-			if !deepEqual(x.Index(i), y.Index(i), visited) {
+			if !deepEqual(x.Index(i), y.Index(i), visited, opts, path.index(i)) {
 				return false
 			}
 		}
@@ -116,20 +145,32 @@ func deepEqual(x reflect.Value, y reflect.Value, visited map[visit]bool) bool {
 		if x.IsNil() || y.IsNil() {
 			return x.IsNil() == y.IsNil()
 		}
-		return deepEqual(x.Elem(), y.Elem(), visited)
+		if x.Elem().Type() != y.Elem().Type() {
+			return false
+		}
+		return deepEqual(x.Elem(), y.Elem(), visited, opts, path)
 	case reflect.Pointer:
 		if x.UnsafePointer() == y.UnsafePointer() {
 			return true
 		}
-		return deepEqual(x.Elem(), y.Elem(), visited)
+		return deepEqual(x.Elem(), y.Elem(), visited, opts, path)
 	case reflect.Struct:
 		for i, n := 0, x.NumField(); i < n; i++ {
-			if !deepEqual(getField(x, i), getField(y, i), visited) {
+			f := x.Type().Field(i)
+			fpath := path.field(f.Name)
+			if opts.ignoreField(f, fpath) {
+				continue
+			}
+
+			if !deepEqual(getField(x, i), getField(y, i), visited, opts, fpath) {
 				return false
 			}
 		}
 		return true
 	case reflect.Map:
+		if opts.equateEmpty && x.Len() == 0 && y.Len() == 0 {
+			return true
+		}
 		if x.IsNil() != y.IsNil() {
 			return false
 		}
@@ -142,7 +183,7 @@ func deepEqual(x reflect.Value, y reflect.Value, visited map[visit]bool) bool {
 		for _, k := range x.MapKeys() {
 			val1 := x.MapIndex(k)
 			val2 := y.MapIndex(k)
-			if !val1.IsValid() || !val2.IsValid() || !deepEqual(val1, val2, visited) {
+			if !val1.IsValid() || !val2.IsValid() || !deepEqual(val1, val2, visited, opts, path.key(k)) {
 				return false
 			}
 		}
@@ -162,15 +203,17 @@ func deepEqual(x reflect.Value, y reflect.Value, visited map[visit]bool) bool {
 	case reflect.Bool:
 		return x.Bool() == y.Bool()
 	case reflect.Float32, reflect.Float64:
-		return x.Float() == y.Float()
+		return opts.floats.equal(x.Float(), y.Float(), x.Type().Bits(), false)
 	case reflect.Complex64, reflect.Complex128:
-		return x.Complex() == y.Complex()
+		return opts.floats.equalComplex(x.Complex(), y.Complex(), x.Type().Bits())
 	default:
 		// Don't want to replicate reflect magic, just delegate to the ol'good reflect.DeepEqual
 		return reflect.DeepEqual(x.Interface(), y.Interface())
 	}
 }
 
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
 type visit struct {
 	a1  unsafe.Pointer
 	a2  unsafe.Pointer
@@ -1,9 +1,17 @@
 package deepequal_test
 
 import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
 	"github.com/sirkon/deepequal"
 	"github.com/sirkon/deepequal/internal/testdata"
-	"testing"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 func TestEqual(t *testing.T) {
@@ -31,6 +39,24 @@ func TestEqual(t *testing.T) {
 			y:    map[string]int{},
 			want: false,
 		},
+		{
+			name: "array match",
+			x:    [2]int{1, 2},
+			y:    [2]int{1, 2},
+			want: true,
+		},
+		{
+			name: "array mismatch",
+			x:    [2]int{1, 2},
+			y:    [2]int{1, 3},
+			want: false,
+		},
+		{
+			name: "interface dynamic types mismatch",
+			x:    []any{1},
+			y:    []any{int64(1)},
+			want: false,
+		},
 		{
 			name: "direct proto match",
 			x: &testdata.Sample{
@@ -98,3 +124,304 @@ func TestEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestEqualWithComparer(t *testing.T) {
+	type money struct {
+		units int64
+		nanos int32
+	}
+	type order struct {
+		id    string
+		total money
+	}
+
+	sameUnits := func(a, b money) bool {
+		return a.units == b.units
+	}
+	x := order{id: "1", total: money{units: 10, nanos: 1}}
+	y := order{id: "1", total: money{units: 10, nanos: 2}}
+
+	if deepequal.Equal(x, y) {
+		t.Error("values must be different without a comparer")
+	}
+	if !deepequal.EqualWith(x, y, deepequal.WithComparer(sameUnits)) {
+		t.Error("values must be equal with a comparer")
+	}
+	if deepequal.EqualWith(x, order{id: "2", total: y.total}, deepequal.WithComparer(sameUnits)) {
+		t.Error("comparer must not affect other fields")
+	}
+
+	type id string
+	deepequal.RegisterComparer(func(a, b id) bool {
+		return strings.EqualFold(string(a), string(b))
+	})
+	if !deepequal.Equal([]id{"abc"}, []id{"ABC"}) {
+		t.Error("values must be equal with a registered comparer")
+	}
+	if deepequal.EqualWith([]id{"abc"}, []id{"ABC"}, deepequal.WithComparer(func(a, b id) bool { return a == b })) {
+		t.Error("per call comparer must take precedence over the registered one")
+	}
+}
+
+func TestEqualMethods(t *testing.T) {
+	type event struct {
+		name string
+		at   time.Time
+	}
+
+	moment := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	x := event{name: "start", at: moment}
+	y := event{name: "start", at: moment.In(time.FixedZone("UTC+3", 3*60*60))}
+
+	if !deepequal.Equal(x, y) {
+		t.Error("the same instants in different locations must be equal")
+	}
+	if !deepequal.Equal(&x, &y) {
+		t.Error("the same instants in different locations must be equal behind pointers")
+	}
+	if deepequal.EqualWith(x, y, deepequal.IgnoreEqualMethods()) {
+		t.Error("values must be different when Equal methods are ignored")
+	}
+	if !deepequal.Equal(ptrEqualer{v: 1, tag: "a"}, ptrEqualer{v: 1, tag: "b"}) {
+		t.Error("Equal method with pointer receiver must be used")
+	}
+	if deepequal.Equal(ptrEqualer{v: 1}, ptrEqualer{v: 2}) {
+		t.Error("Equal method with pointer receiver must be used")
+	}
+	if !deepequal.Equal([]*ptrEqualer{nil, {v: 1}}, []*ptrEqualer{nil, {v: 1, tag: "b"}}) {
+		t.Error("Equal method with pointer receiver must be used for pointers")
+	}
+}
+
+type ptrEqualer struct {
+	v   int
+	tag string
+}
+
+func (p *ptrEqualer) Equal(q *ptrEqualer) bool {
+	return p.v == q.v
+}
+
+func TestEqualIgnoreFields(t *testing.T) {
+	type item struct {
+		Name      string
+		CreatedAt time.Time
+	}
+	type order struct {
+		ID      string `deepequal:"-"`
+		Items   []item
+		Version int
+	}
+
+	x := order{
+		ID:      "1",
+		Items:   []item{{Name: "a", CreatedAt: time.Unix(1, 0)}},
+		Version: 1,
+	}
+	y := order{
+		ID:      "2",
+		Items:   []item{{Name: "a", CreatedAt: time.Unix(2, 0)}},
+		Version: 2,
+	}
+
+	if deepequal.Equal(x, y) {
+		t.Error("values must be different")
+	}
+
+	ignoreVersion := deepequal.IgnoreFieldsFunc(func(f reflect.StructField) bool {
+		return f.Name == "Version"
+	})
+	if !deepequal.EqualWith(x, y, deepequal.IgnorePaths("order.Items[*].CreatedAt"), ignoreVersion) {
+		t.Error("values must be equal with ignored fields")
+	}
+	if !deepequal.EqualWith(&x, &y, deepequal.IgnorePaths(".Items[0].CreatedAt"), ignoreVersion) {
+		t.Error("values must be equal with ignored fields behind pointers")
+	}
+	if deepequal.EqualWith(x, y, deepequal.IgnorePaths("Items[1].CreatedAt"), ignoreVersion) {
+		t.Error("values must be different when unrelated paths are ignored")
+	}
+}
+
+func TestEqualIgnoreOrder(t *testing.T) {
+	type set struct {
+		Name  string
+		Items []int
+	}
+
+	x := set{Name: "a", Items: []int{1, 2, 2, 3}}
+	y := set{Name: "a", Items: []int{2, 3, 2, 1}}
+	z := set{Name: "a", Items: []int{2, 3, 3, 1}}
+
+	if deepequal.Equal(x, y) {
+		t.Error("values must be different")
+	}
+	if !deepequal.EqualWith(x, y, deepequal.IgnoreOrder()) {
+		t.Error("values must be equal regardless of order")
+	}
+	if !deepequal.EqualWith(x, y, deepequal.IgnoreOrderAt("set.Items")) {
+		t.Error("values must be equal regardless of order at the path")
+	}
+	if !deepequal.EqualWith(x, y, deepequal.IgnoreOrderOf[int]()) {
+		t.Error("values must be equal regardless of order of integers")
+	}
+	if deepequal.EqualWith(x, y, deepequal.IgnoreOrderOf[string]()) {
+		t.Error("values must be different when order of other slices is ignored")
+	}
+	if deepequal.EqualWith(x, z, deepequal.IgnoreOrder()) {
+		t.Error("multisets with different multiplicities must be different")
+	}
+
+	a := &testdata.Order{
+		Id:    "1",
+		Items: []*testdata.Item{{Name: "a", Price: 1}, {Name: "b", Price: 2}},
+		Tags:  []string{"x", "y"},
+	}
+	b := &testdata.Order{
+		Id:    "1",
+		Items: []*testdata.Item{{Name: "b", Price: 2}, {Name: "a", Price: 1}},
+		Tags:  []string{"y", "x"},
+	}
+
+	if deepequal.Equal(a, b) {
+		t.Error("proto messages must be different")
+	}
+	if !deepequal.EqualWith(a, b, deepequal.IgnoreOrder()) {
+		t.Error("proto messages must be equal regardless of order")
+	}
+	if deepequal.EqualWith(a, b, deepequal.IgnoreOrderAt("Order.items")) {
+		t.Error("proto messages must be different with unordered items only")
+	}
+	if !deepequal.EqualWith(a, b, deepequal.IgnoreOrderAt("Order.items", "Order.tags")) {
+		t.Error("proto messages must be equal with unordered items and tags")
+	}
+	if deepequal.EqualWith(a, b, deepequal.IgnoreOrderOf[*testdata.Item]()) {
+		t.Error("proto messages must be different with unordered items only")
+	}
+}
+
+func TestEqualFloats(t *testing.T) {
+	type measure struct {
+		Value float64
+		Ratio float32
+		Point complex128
+	}
+
+	sum := 0.1
+	sum += 0.2
+	x := measure{Value: sum, Ratio: 1, Point: complex(1, math.NaN())}
+	y := measure{Value: 0.3, Ratio: math.Nextafter32(1, 2), Point: complex(1, math.NaN())}
+	nan := measure{Value: math.NaN()}
+
+	if deepequal.Equal(x, y) {
+		t.Error("values must be different")
+	}
+	if deepequal.Equal(nan, nan) {
+		t.Error("NaNs must be different by default")
+	}
+	if !deepequal.EqualWith(nan, nan, deepequal.EquateNaNs()) {
+		t.Error("NaNs must be equal with EquateNaNs")
+	}
+	if !deepequal.EqualWith(x, y, deepequal.EquateNaNs(), deepequal.FloatAbsTolerance(1e-6)) {
+		t.Error("values must be equal with absolute tolerance")
+	}
+	if !deepequal.EqualWith(x, y, deepequal.EquateNaNs(), deepequal.FloatRelTolerance(1e-6)) {
+		t.Error("values must be equal with relative tolerance")
+	}
+	if !deepequal.EqualWith(x, y, deepequal.EquateNaNs(), deepequal.FloatULPTolerance(1)) {
+		t.Error("values must be equal with 1 ULP tolerance")
+	}
+	if deepequal.EqualWith(measure{Value: 1}, measure{Value: 1.1}, deepequal.FloatULPTolerance(1000)) {
+		t.Error("values must be different with ULP tolerance")
+	}
+	if deepequal.EqualWith(measure{Value: math.Inf(1)}, measure{Value: math.MaxFloat64}, deepequal.FloatRelTolerance(1)) {
+		t.Error("infinity must not be equal to finite values")
+	}
+	// 1.0 is close to both 1.4 and 0.9, but only 0.9 is close to 1.0 alone.
+	if !deepequal.EqualWith([]float64{1.0, 1.5}, []float64{1.4, 0.9}, deepequal.IgnoreOrder(), deepequal.FloatAbsTolerance(0.5)) {
+		t.Error("values must be equal regardless of order with tolerance")
+	}
+	if deepequal.EqualWith([]float64{1.0, 2.5}, []float64{1.4, 0.9}, deepequal.IgnoreOrder(), deepequal.FloatAbsTolerance(0.5)) {
+		t.Error("values must be different regardless of order with tolerance")
+	}
+
+	a := &testdata.Item{Name: "a", Weight: sum, Discount: 0.5}
+	b := &testdata.Item{Name: "a", Weight: 0.3, Discount: 0.5}
+
+	if deepequal.Equal(a, b) {
+		t.Error("proto messages must be different")
+	}
+	if !deepequal.EqualWith(a, b, deepequal.FloatAbsTolerance(1e-9)) {
+		t.Error("proto messages must be equal with absolute tolerance")
+	}
+	if !deepequal.Equal(&testdata.Item{Weight: math.NaN()}, &testdata.Item{Weight: math.NaN()}) {
+		t.Error("NaN fields of proto messages must be equal")
+	}
+	if !deepequal.EqualWith(&testdata.Item{Weight: math.NaN()}, &testdata.Item{Weight: math.NaN()}, deepequal.FloatAbsTolerance(1)) {
+		t.Error("NaN fields of proto messages must be equal with tolerance")
+	}
+}
+
+func TestEqualProtoOptions(t *testing.T) {
+	a := &testdata.Order{
+		Id:    "1",
+		Items: []*testdata.Item{{Name: "a", Price: 1}, {Name: "b", Price: 2}},
+		Tags:  []string{"x", "y"},
+	}
+	b := &testdata.Order{
+		Id:    "1",
+		Items: []*testdata.Item{{Name: "a", Price: 3}, {Name: "b", Price: 4}},
+		Tags:  []string{"y", "x"},
+	}
+	b.ProtoReflect().SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 1000, protowire.VarintType), 5))
+
+	opts := []deepequal.Option{
+		deepequal.IgnoreProtoFields("sample.Item.price"),
+		deepequal.IgnoreProtoOrder("sample.Order.tags"),
+		deepequal.IgnoreProtoUnknown(),
+	}
+	if !deepequal.EqualWith(a, b, opts...) {
+		t.Error("proto messages must be equal with ignored prices, unknown fields and tags order")
+	}
+	if report := deepequal.Report(a, b, opts...); len(report) != 0 {
+		t.Errorf("empty report expected, got\n%s", report)
+	}
+
+	opts[0] = deepequal.IgnoreProtoFields("sample.Item.2")
+	if !deepequal.EqualWith(a, b, opts...) {
+		t.Error("proto messages must be equal with prices ignored by number")
+	}
+	if deepequal.EqualWith(a, b, opts[:2]...) {
+		t.Error("proto messages must be different with unknown fields")
+	}
+	if deepequal.EqualWith(a, b, opts[0], opts[2]) {
+		t.Error("proto messages must be different with ordered tags")
+	}
+
+	x := &testdata.Envelope{Id: proto.String(""), Payload: &testdata.Envelope_Item{Item: &testdata.Item{}}}
+	y := &testdata.Envelope{}
+	if deepequal.Equal(x, y) {
+		t.Error("proto messages with fields set to defaults must be different from empty one")
+	}
+	if !deepequal.EqualWith(x, y, deepequal.IgnoreProtoDefaults()) {
+		t.Error("proto messages with fields set to defaults must be equal to empty one")
+	}
+
+	// Serialized Any contents with the same fields in a different order.
+	item := protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), "a")
+	price := protowire.AppendVarint(protowire.AppendTag(nil, 2, protowire.VarintType), 1)
+	anyX := &anypb.Any{TypeUrl: "type.googleapis.com/sample.Item", Value: append(append([]byte{}, item...), price...)}
+	anyY := &anypb.Any{TypeUrl: "type.googleapis.com/sample.Item", Value: append(append([]byte{}, price...), item...)}
+	if deepequal.Equal(anyX, anyY) {
+		t.Error("Any messages must be different by their bytes")
+	}
+	if !deepequal.EqualWith(anyX, anyY, deepequal.EquateProtoAny()) {
+		t.Error("Any messages must be equal by their contents")
+	}
+	anyY.Value = protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), "b")
+	if deepequal.EqualWith(anyX, anyY, deepequal.EquateProtoAny()) {
+		t.Error("Any messages with different contents must be different")
+	}
+	if report := deepequal.Report(anyX, anyY, deepequal.EquateProtoAny()); len(report) != 1 {
+		t.Errorf("a difference of Any contents expected, got\n%s", report)
+	}
+}
@@ -9,7 +9,7 @@ import (
 )
 
 // Builds a difference between left and right values.
-func difference(l, r reflect.Value, isProto bool, stack walkSet) diff.Diff {
+func difference(l, r reflect.Value, isProto bool, stack walkSet, opts *options, path valuePath) diff.Diff {
 	if !stack.use(l) {
 		return nil
 	}
@@ -21,22 +21,29 @@ func difference(l, r reflect.Value, isProto bool, stack walkSet) diff.Diff {
 		panic(fmt.Errorf("right is %s", r.String()))
 	}
 
-	if Equal(l.Interface(), r.Interface()) {
+	if equalValues(l, r, opts, path) {
 		return nil
 	}
 
-	if l.Kind() != r.Kind() {
+	if l.Type() != r.Type() {
 		return &diff.Type{
 			Left:  l.Type().String(),
 			Right: r.Type().String(),
 		}
 	}
 
+	if opts.comparer(l.Type()) != nil || opts.equalMethod(l.Type()) != nil {
+		// Custom comparers and Equal methods are opaque, can't look into the values deeper.
+		return &diff.Value{}
+	}
+
 	switch l.Kind() {
-	case reflect.Bool, reflect.String,
+	case reflect.String:
+		return textDifference(l.String(), r.String())
+	case reflect.Bool,
 		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
-		reflect.Float32, reflect.Float64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
 		reflect.Uintptr, reflect.UnsafePointer:
 		return &diff.Value{}
 	case reflect.Slice, reflect.Array:
@@ -49,11 +56,14 @@ func difference(l, r reflect.Value, isProto bool, stack walkSet) diff.Diff {
 			return &diff.Value{}
 		}
 
-		common := lcs(l, r)
-		return &diff.Indices{
-			Left:  findUncommon(l, common),
-			Right: findUncommon(r, common),
+		if l.Kind() == reflect.Slice && opts.unordered(l.Type().Elem(), path) {
+			return unorderedDifference(l, r, opts, path)
 		}
+		if isBytes(l.Type()) {
+			return bytesDifference(bytesOf(l), bytesOf(r))
+		}
+
+		return orderedDifference(l, r, opts, path)
 
 	case reflect.Map:
 		if l.IsZero() || r.IsZero() {
@@ -73,7 +83,7 @@ func difference(l, r reflect.Value, isProto bool, stack walkSet) diff.Diff {
 				continue
 			}
 
-			if v := difference(l.MapIndex(key), rv, isProto, stack); v != nil {
+			if v := difference(l.MapIndex(key), rv, isProto, stack, opts, path.key(key)); v != nil {
 				res.Left[key.Interface()] = v
 			}
 		}
@@ -85,7 +95,7 @@ func difference(l, r reflect.Value, isProto bool, stack walkSet) diff.Diff {
 				continue
 			}
 
-			if v := difference(lv, r.MapIndex(key), isProto, stack); v != nil {
+			if v := difference(lv, r.MapIndex(key), isProto, stack, opts, path.key(key)); v != nil {
 				res.Right[key.Interface()] = v
 			}
 		}
@@ -102,10 +112,20 @@ func difference(l, r reflect.Value, isProto bool, stack walkSet) diff.Diff {
 				// Pass unexported fields in proto message.
 				continue
 			}
+			fname := l.Type().Field(i).Name
+			if name, ok := protoFieldName(l.Type().Field(i)); ok && isProto {
+				// Proto names are used in paths within protobuf messages.
+				fname = name
+			}
+			fpath := path.field(fname)
+			if opts.ignoreField(l.Type().Field(i), fpath) {
+				continue
+			}
+
 			fl := getField(l, i)
 			fr := getField(r, i)
 
-			if v := difference(fl, fr, isProto, stack); v != nil {
+			if v := difference(fl, fr, isProto, stack, opts, fpath); v != nil {
 				res.Fields[l.Type().Field(i).Name] = v
 			}
 		}
@@ -116,55 +136,62 @@ func difference(l, r reflect.Value, isProto bool, stack walkSet) diff.Diff {
 			return &diff.Value{}
 		}
 
+		if mx, ok := getProtoMessage(l.Interface()); ok {
+			if my, ok := getProtoMessage(r.Interface()); ok {
+				return protoDifference(mx.ProtoReflect(), my.ProtoReflect(), opts, path)
+			}
+		}
+
 		_, isProto = l.Interface().(proto.Message)
-		return difference(l.Elem(), r.Elem(), isProto, stack)
+		return difference(l.Elem(), r.Elem(), isProto, stack, opts, path)
 
 	default:
-		panic(fmt.Errorf("cannot diff values of %T", l.Interface()))
+		// Functions and channels are only equal when they are nil both, nothing to look into.
+		return &diff.Value{}
 	}
 }
 
-func lcs(x, y reflect.Value) reflect.Value {
-	if x.Len() == 0 || y.Len() == 0 {
-		return reflect.Zero(x.Type())
-	}
+// unorderedDifference builds a difference of slices compared regardless of elements order.
+func unorderedDifference(l, r reflect.Value, opts *options, path valuePath) diff.Diff {
+	left, right := matchMultisets(l.Len(), r.Len(), func(i, j int) bool {
+		return equalValues(l.Index(i), r.Index(j), opts, path.index(i))
+	})
 
-	xlast := x.Index(x.Len() - 1)
-	ylast := y.Index(y.Len() - 1)
-
-	if Equal(xlast.Interface(), ylast.Interface()) {
-		res := lcs(x.Slice(0, x.Len()-1), y.Slice(0, y.Len()-1))
-		return reflect.Append(res, xlast)
+	res := &diff.Indices{
+		Left:  map[int]diff.Diff{},
+		Right: map[int]diff.Diff{},
 	}
-
-	first := lcs(x.Slice(0, x.Len()-1), y)
-	second := lcs(x, y.Slice(0, y.Len()-1))
-	if first.Len() < second.Len() {
-		return second
+	for _, i := range left {
+		res.Left[i] = &diff.Missing{}
+	}
+	for _, j := range right {
+		res.Right[j] = &diff.Missing{}
 	}
 
-	return first
+	return res
 }
 
-func findUncommon(src, known reflect.Value) map[int]diff.Diff {
-	info := map[int]diff.Diff{}
+// orderedDifference builds a difference of sequences using the shortest edit script between them.
+func orderedDifference(l, r reflect.Value, opts *options, path valuePath) diff.Diff {
+	left, right := sequenceDiff(l.Len(), r.Len(), func(i, j int) bool {
+		return equalValues(l.Index(i), r.Index(j), opts, path.index(i))
+	})
 
-	var j int
-	for i := 0; i < src.Len(); i++ {
-		if j >= known.Len() {
-			info[i] = &diff.Missing{}
-			continue
-		}
+	return &diff.Indices{
+		Left:  missingIndices(left),
+		Right: missingIndices(right),
+	}
+}
 
-		if Equal(src.Index(i).Interface(), known.Index(j).Interface()) {
-			j++
-			continue
+func missingIndices(missing []bool) map[int]diff.Diff {
+	res := map[int]diff.Diff{}
+	for i, ok := range missing {
+		if ok {
+			res[i] = &diff.Missing{}
 		}
-
-		info[i] = &diff.Missing{}
 	}
 
-	return info
+	return res
 }
 
 type walkSet map[reflect.Value]struct{}
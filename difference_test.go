@@ -59,11 +59,26 @@ func TestDifference(t *testing.T) {
 					},
 				},
 				{
-					name:  "unsupported type",
-					a:     make(chan struct{}),
-					b:     make(chan struct{}),
-					want:  nil,
-					panic: true,
+					name: "same kinds of different types",
+					a:    struct{ V any }{V: sampleStruct{a: 1}},
+					b:    struct{ V any }{V: struct{ a int }{a: 1}},
+					want: &diff.Fields{
+						Fields: map[string]diff.Diff{
+							"V": &diff.Type{Left: "deepequal.sampleStruct", Right: "struct { a int }"},
+						},
+					},
+				},
+				{
+					name: "channels",
+					a:    make(chan struct{}),
+					b:    make(chan struct{}),
+					want: &diff.Value{},
+				},
+				{
+					name: "functions",
+					a:    func() {},
+					b:    func() {},
+					want: &diff.Value{},
 				},
 				{
 					name:  "invalid a type",
@@ -85,9 +100,9 @@ func TestDifference(t *testing.T) {
 					b:    proto2,
 					want: &diff.Fields{
 						Fields: map[string]diff.Diff{
-							"Sub": &diff.Fields{
+							"sub": &diff.Fields{
 								Fields: map[string]diff.Diff{
-									"Val": &diff.Value{},
+									"val": &diff.Value{},
 								},
 							},
 						},
@@ -342,7 +357,7 @@ func TestDifference(t *testing.T) {
 							t.Error("panic was expected")
 						}()
 					}
-					got := difference(reflect.ValueOf(ttt.a), reflect.ValueOf(ttt.b), false, walkSet{})
+					got := difference(reflect.ValueOf(ttt.a), reflect.ValueOf(ttt.b), false, walkSet{}, newOptions(nil), valuePath{})
 					if !reflect.DeepEqual(got, ttt.want) {
 						t.Error("want\n", spew.Sdump(ttt.want), "\ngot\n", spew.Sdump(got))
 					}
@@ -353,6 +368,194 @@ func TestDifference(t *testing.T) {
 	}
 }
 
+func TestDifferenceIgnoreFields(t *testing.T) {
+	type sample struct {
+		ID    string `deepequal:"-"`
+		Name  string
+		Stamp int
+	}
+
+	a := reflect.ValueOf(sample{ID: "1", Name: "a", Stamp: 1})
+	b := reflect.ValueOf(sample{ID: "2", Name: "b", Stamp: 2})
+	opts := newOptions([]Option{IgnorePaths("sample.Stamp")})
+
+	got := difference(a, b, false, walkSet{}, opts, opts.pathFor(a))
+	want := &diff.Fields{
+		Fields: map[string]diff.Diff{
+			"Name": &diff.Value{},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("want\n", spew.Sdump(want), "\ngot\n", spew.Sdump(got))
+	}
+}
+
+func TestDifferenceIgnoreOrder(t *testing.T) {
+	a := reflect.ValueOf([]int{1, 2, 2, 3, 4})
+	b := reflect.ValueOf([]int{5, 2, 3, 1, 2, 2})
+	opts := newOptions([]Option{IgnoreOrder()})
+
+	got := difference(a, b, false, walkSet{}, opts, opts.pathFor(a))
+	want := &diff.Indices{
+		Left: map[int]diff.Diff{
+			4: &diff.Missing{},
+		},
+		Right: map[int]diff.Diff{
+			0: &diff.Missing{},
+			5: &diff.Missing{},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("want\n", spew.Sdump(want), "\ngot\n", spew.Sdump(got))
+	}
+}
+
+func TestDifferenceEquateEmpty(t *testing.T) {
+	opts := newOptions([]Option{EquateEmpty()})
+	tests := []struct {
+		name string
+		a    any
+		b    any
+		want diff.Diff
+	}{
+		{
+			name: "empty and nil slice",
+			a:    []int{},
+			b:    []int(nil),
+			want: nil,
+		},
+		{
+			name: "empty and nil map",
+			a:    map[int]bool(nil),
+			b:    map[int]bool{},
+			want: nil,
+		},
+		{
+			name: "nil and not empty slice",
+			a:    []int(nil),
+			b:    []int{1},
+			want: &diff.Value{},
+		},
+		{
+			name: "nested empty and nil",
+			a: sampleEmpties{
+				Slice: []string{},
+				Map:   nil,
+			},
+			b: sampleEmpties{
+				Slice: nil,
+				Map:   map[string]int{},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := reflect.ValueOf(tt.a)
+			got := difference(a, reflect.ValueOf(tt.b), false, walkSet{}, opts, opts.pathFor(a))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Error("want\n", spew.Sdump(tt.want), "\ngot\n", spew.Sdump(got))
+			}
+		})
+	}
+}
+
+func TestDifferenceText(t *testing.T) {
+	opts := newOptions(nil)
+	tests := []struct {
+		name string
+		a    any
+		b    any
+		want diff.Diff
+	}{
+		{
+			name: "runes",
+			a:    "hello world",
+			b:    "hello, world",
+			want: &diff.Text{
+				Left: []diff.Segment{
+					{Text: "hello world"},
+				},
+				Right: []diff.Segment{
+					{Text: "hello"},
+					{Text: ",", Changed: true},
+					{Text: " world"},
+				},
+			},
+		},
+		{
+			name: "too different",
+			a:    "abcdef",
+			b:    "uvwxyz",
+			want: &diff.Value{},
+		},
+		{
+			name: "text bytes",
+			a:    []byte("hello world"),
+			b:    []byte("hello, world"),
+			want: &diff.Text{
+				Left: []diff.Segment{
+					{Text: "hello world"},
+				},
+				Right: []diff.Segment{
+					{Text: "hello"},
+					{Text: ",", Changed: true},
+					{Text: " world"},
+				},
+			},
+		},
+		{
+			name: "binary bytes",
+			a:    []byte{0, 1, 2, 0xff},
+			b:    []byte{0, 1, 3, 0xff, 4},
+			want: &diff.Indices{
+				Left: map[int]diff.Diff{
+					2: &diff.Missing{},
+				},
+				Right: map[int]diff.Diff{
+					2: &diff.Missing{},
+					4: &diff.Missing{},
+				},
+			},
+		},
+		{
+			name: "lines",
+			a:    "SELECT *\nFROM users\nWHERE id = 1\n",
+			b:    "SELECT *\nFROM users\nWHERE id = 2\nLIMIT 1",
+			want: &diff.Text{
+				Lines: true,
+				Left: []diff.Segment{
+					{Text: "SELECT *\n"},
+					{Text: "FROM users\n"},
+					{Text: "WHERE id = 1\n", Changed: true},
+				},
+				Right: []diff.Segment{
+					{Text: "SELECT *\n"},
+					{Text: "FROM users\n"},
+					{Text: "WHERE id = 2\n", Changed: true},
+					{Text: "LIMIT 1", Changed: true},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := reflect.ValueOf(tt.a)
+			got := difference(a, reflect.ValueOf(tt.b), false, walkSet{}, opts, opts.pathFor(a))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Error("want\n", spew.Sdump(tt.want), "\ngot\n", spew.Sdump(got))
+			}
+		})
+	}
+}
+
+type sampleEmpties struct {
+	Slice []string
+	Map   map[string]int
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
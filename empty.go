@@ -0,0 +1,8 @@
+package deepequal
+
+// EquateEmpty makes nil and empty slices and maps equal. Nil values are printed as empty ones then.
+func EquateEmpty() Option {
+	return func(o *options) {
+		o.equateEmpty = true
+	}
+}
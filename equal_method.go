@@ -0,0 +1,104 @@
+package deepequal
+
+import (
+	"reflect"
+	"sync"
+)
+
+// IgnoreEqualMethods turns off the usage of Equal(T) bool methods of compared values.
+func IgnoreEqualMethods() Option {
+	return func(o *options) {
+		o.noEqualMethods = true
+	}
+}
+
+// equalMethod is a wrapper for Equal(T) bool method of type T or *T.
+type equalMethod struct {
+	fn      reflect.Value
+	recvPtr bool
+	argPtr  bool
+}
+
+// equalMethods caches equalMethod lookups. Values are *equalMethod,
+// nil means there is no suitable method for the type.
+var equalMethods sync.Map
+
+// equalMethod returns Equal(T) bool method wrapper for the given type if there is any.
+func (o *options) equalMethod(t reflect.Type) *equalMethod {
+	if o.noEqualMethods {
+		return nil
+	}
+
+	if m, ok := equalMethods.Load(t); ok {
+		return m.(*equalMethod)
+	}
+
+	m := lookupEqualMethod(t)
+	equalMethods.Store(t, m)
+	return m
+}
+
+func (m *equalMethod) call(x, y reflect.Value) bool {
+	if x.Kind() == reflect.Pointer && (x.IsNil() || y.IsNil()) {
+		return x.IsNil() && y.IsNil()
+	}
+
+	if m.recvPtr {
+		x = addressOf(x)
+	}
+	if m.argPtr {
+		y = addressOf(y)
+	}
+
+	return m.fn.Call([]reflect.Value{x, y})[0].Bool()
+}
+
+func lookupEqualMethod(t reflect.Type) *equalMethod {
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+
+	if m, ok := t.MethodByName("Equal"); ok && isEqualMethod(m, t) {
+		return &equalMethod{fn: m.Func}
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return nil
+	}
+
+	pt := reflect.PointerTo(t)
+	m, ok := pt.MethodByName("Equal")
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case isEqualMethod(m, t):
+		return &equalMethod{fn: m.Func, recvPtr: true}
+	case isEqualMethod(m, pt):
+		return &equalMethod{fn: m.Func, recvPtr: true, argPtr: true}
+	default:
+		return nil
+	}
+}
+
+// isEqualMethod checks if the method has func(arg) bool signature.
+func isEqualMethod(m reflect.Method, arg reflect.Type) bool {
+	mt := m.Type
+	if mt.NumIn() != 2 || mt.NumOut() != 1 {
+		return false
+	}
+
+	return mt.In(1) == arg && mt.Out(0).Kind() == reflect.Bool
+}
+
+// addressOf returns a pointer to the value, copies it if the value is not addressable.
+func addressOf(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v.Addr()
+	}
+
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p
+}
@@ -0,0 +1,110 @@
+package deepequal
+
+import (
+	"math"
+)
+
+// FloatAbsTolerance makes floating point values (including float and double fields
+// of protobuf messages) equal when their absolute difference is not greater than eps.
+func FloatAbsTolerance(eps float64) Option {
+	return func(o *options) {
+		o.floats.abs = eps
+	}
+}
+
+// FloatRelTolerance makes floating point values (including float and double fields
+// of protobuf messages) equal when their absolute difference is not greater than
+// frac multiplied by the largest magnitude of them.
+func FloatRelTolerance(frac float64) Option {
+	return func(o *options) {
+		o.floats.rel = frac
+	}
+}
+
+// FloatULPTolerance makes floating point values (including float and double fields
+// of protobuf messages) equal when there are no more than ulps representable values
+// of their type between them.
+func FloatULPTolerance(ulps uint64) Option {
+	return func(o *options) {
+		o.floats.ulps = ulps
+	}
+}
+
+// EquateNaNs makes NaN values of Go floats and complex numbers equal to each other.
+// NaN fields of protobuf messages are always equal, just like proto.Equal treats them.
+func EquateNaNs() Option {
+	return func(o *options) {
+		o.floats.nans = true
+	}
+}
+
+// floatOptions is a set of floating point comparison rules.
+type floatOptions struct {
+	abs  float64
+	rel  float64
+	ulps uint64
+	nans bool
+}
+
+// custom checks if any rule differs from the plain == comparison.
+func (f floatOptions) custom() bool {
+	return f.abs > 0 || f.rel > 0 || f.ulps > 0
+}
+
+// equal compares floating point values of the given bit size.
+func (f floatOptions) equal(x, y float64, bits int, nans bool) bool {
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return (f.nans || nans) && math.IsNaN(x) && math.IsNaN(y)
+	}
+
+	if x == y {
+		return true
+	}
+
+	if math.IsInf(x, 0) || math.IsInf(y, 0) {
+		return false
+	}
+
+	delta := math.Abs(x - y)
+	if delta <= f.abs {
+		return true
+	}
+
+	if delta <= f.rel*math.Max(math.Abs(x), math.Abs(y)) {
+		return true
+	}
+
+	return f.ulps > 0 && ulpDistance(x, y, bits) <= f.ulps
+}
+
+func (f floatOptions) equalComplex(x, y complex128, bits int) bool {
+	return f.equal(real(x), real(y), bits/2, false) && f.equal(imag(x), imag(y), bits/2, false)
+}
+
+// ulpDistance returns the number of representable values of the given bit size between x and y.
+func ulpDistance(x, y float64, bits int) uint64 {
+	var a, b uint64
+	if bits == 32 {
+		a = ordered(uint64(math.Float32bits(float32(x))), 32)
+		b = ordered(uint64(math.Float32bits(float32(y))), 32)
+	} else {
+		a = ordered(math.Float64bits(x), 64)
+		b = ordered(math.Float64bits(y), 64)
+	}
+
+	if a < b {
+		return b - a
+	}
+
+	return a - b
+}
+
+// ordered maps sign-magnitude float bits to a monotonic integer scale.
+func ordered(b uint64, bits int) uint64 {
+	sign := uint64(1) << (bits - 1)
+	if b&sign != 0 {
+		return sign - (b &^ sign)
+	}
+
+	return sign + b
+}
@@ -0,0 +1,96 @@
+package deepequal
+
+import (
+	"fmt"
+)
+
+// FoldUnchanged collapses unchanged struct fields, slice elements and map entries
+// into "... N identical fields ..." markers in the side-by-side output. Entries that
+// are not further than context entries from a difference are kept.
+func FoldUnchanged(context int) Option {
+	return func(o *options) {
+		if context < 0 {
+			context = 0
+		}
+		o.fold = true
+		o.foldContext = context
+	}
+}
+
+// folded returns entries of a container with n entries to be collapsed.
+// Returns nil if nothing is to be collapsed.
+func (p *printer) folded(n int, changed func(i int) bool) []bool {
+	if !p.opts.fold {
+		return nil
+	}
+
+	// Distance from every entry to the closest changed one.
+	dist := make([]int, n)
+	last := -1
+	for i := 0; i < n; i++ {
+		if changed(i) {
+			last = i
+		}
+		if last < 0 {
+			dist[i] = n
+		} else {
+			dist[i] = i - last
+		}
+	}
+	last = -1
+	for i := n - 1; i >= 0; i-- {
+		if changed(i) {
+			last = i
+		}
+		if last >= 0 && last-i < dist[i] {
+			dist[i] = last - i
+		}
+	}
+
+	res := make([]bool, n)
+	var found bool
+	for i := 0; i < n; {
+		j := i
+		for j < n && dist[j] > p.opts.foldContext {
+			j++
+		}
+
+		// There's no point to replace a single line with a marker.
+		if j-i > 1 {
+			for k := i; k < j; k++ {
+				res[k] = true
+			}
+			found = true
+		}
+		if j == i {
+			j++
+		}
+		i = j
+	}
+
+	if !found {
+		return nil
+	}
+
+	return res
+}
+
+// printFolded prints a marker of collapsed entries.
+func (p *printer) printFolded(offset string, count int, what string) {
+	p.buf.WriteString(offset)
+	p.escape(formatDim)
+	_, _ = fmt.Fprintf(p.buf, "... %d identical %s ...", count, what)
+	p.escape(formatReset)
+	p.setColorOn()
+	p.buf.WriteString("\n\r")
+}
+
+// foldRun returns the length of the run of collapsed entries starting at i.
+func foldRun(folded []bool, i int) int {
+	j := i
+	for j < len(folded) && folded[j] {
+		j++
+	}
+
+	return j - i
+}
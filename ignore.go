@@ -0,0 +1,66 @@
+package deepequal
+
+import (
+	"reflect"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ignoreTag is a struct tag key to exclude fields from comparisons with `deepequal:"-"`.
+const ignoreTag = "deepequal"
+
+// IgnorePaths excludes struct fields matched by given path expressions from comparisons.
+// Expressions look like Order.Items[*].CreatedAt, where
+//
+//   - The leading root type name is optional, .Items[*].CreatedAt and Items[*].CreatedAt are fine too.
+//   - [*] matches any slice index or map key, [3] and ["key"] match specific ones.
+//   - .* matches any field.
+//
+// Pointers and interfaces have no steps of their own. Panics on malformed expressions.
+func IgnorePaths(exprs ...string) Option {
+	patterns := make([]pathPattern, len(exprs))
+	for i, expr := range exprs {
+		patterns[i] = mustParsePattern(expr)
+	}
+
+	return func(o *options) {
+		o.ignorePaths = append(o.ignorePaths, patterns...)
+	}
+}
+
+// IgnoreFieldsFunc excludes struct fields the predicate returns true for from comparisons.
+func IgnoreFieldsFunc(pred func(f reflect.StructField) bool) Option {
+	return func(o *options) {
+		o.ignoreFuncs = append(o.ignoreFuncs, pred)
+	}
+}
+
+// ignoreField checks if the field with the given path must be excluded from comparison.
+func (o *options) ignoreField(f reflect.StructField, path valuePath) bool {
+	if f.Tag.Get(ignoreTag) == "-" {
+		return true
+	}
+
+	for _, pred := range o.ignoreFuncs {
+		if pred(f) {
+			return true
+		}
+	}
+
+	return o.ignorePath(path)
+}
+
+// ignoreProtoField checks if the protobuf message field with the given path must be excluded from comparison.
+func (o *options) ignoreProtoField(fd protoreflect.FieldDescriptor, path valuePath) bool {
+	return matchProtoFields(o.proto.ignored, fd) || o.ignorePath(path)
+}
+
+func (o *options) ignorePath(path valuePath) bool {
+	for _, pattern := range o.ignorePaths {
+		if pattern.match(path) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -9,6 +9,11 @@ type oneofDiff struct {
 	}
 	Value   struct{}
 	Missing struct{}
+	Text    struct {
+		Lines bool
+		Left  []Segment
+		Right []Segment
+	}
 
 	Fields struct {
 		Fields map[string]*oneofDiff
@@ -46,6 +51,22 @@ type Missing struct{}
 
 func (*Missing) isDiff() {}
 
+// Text branch of Diff
+type Text struct {
+	Lines bool
+	Left  []Segment
+	Right []Segment
+}
+
+func (*Text) isDiff() {}
+
+// Segment is a piece of a string in a Text difference. Segments are lines
+// of multiline strings and runs of runes otherwise.
+type Segment struct {
+	Text    string
+	Changed bool
+}
+
 // Fields branch of Diff
 type Fields struct {
 	Fields map[string]Diff
@@ -1,3 +1,3 @@
 package testdata
 
-//go:generate protoc --go_out=paths=source_relative:. sample.proto
+//go:generate protoc --go_out=paths=source_relative:. sample.proto order.proto envelope.proto
@@ -0,0 +1,226 @@
+package deepequal
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Width limits the width of the side-by-side output with the given number of characters.
+// Long lines are wrapped by default. The width is taken from COLUMNS environment variable
+// or from the terminal size if not set. The output is not limited if neither is available.
+func Width(n int) Option {
+	return func(o *options) {
+		o.maxWidth = n
+	}
+}
+
+// ElideLongLines makes lines not fitting into the column width to be cut
+// with … marker instead of wrapping.
+func ElideLongLines() Option {
+	return func(o *options) {
+		o.elide = true
+	}
+}
+
+const (
+	wrapMarker  = "↵"
+	elideMarker = "…"
+
+	// minColumnWidth is the narrowest column width: a gutter, a few characters and a marker.
+	// The output gets wider than requested rather than narrower than this.
+	minColumnWidth = 8
+)
+
+// width returns the limit of the output width, 0 means no limit.
+func (o *options) width() int {
+	if o.maxWidth > 0 {
+		return o.maxWidth
+	}
+
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+
+	return terminalWidth(os.Stdout)
+}
+
+// column is a side of the side-by-side output.
+type column struct {
+	lines []string
+	// gutters to prepend lines with. Can be empty.
+	gutters []string
+}
+
+func (c column) gutter(i int) string {
+	if i < len(c.gutters) {
+		return c.gutters[i]
+	}
+
+	return ""
+}
+
+// layoutColumns puts columns side by side keeping their lines aligned.
+// Lines not fitting into the width are either wrapped or elided.
+func layoutColumns(l, r column, width int, elide bool, lineBreak string) string {
+	lwidth := 0
+	for i, line := range l.lines {
+		if w := visibleWidth(l.gutter(i) + line); w > lwidth {
+			lwidth = w
+		}
+	}
+	rwidth := 0
+	for i, line := range r.lines {
+		if w := visibleWidth(r.gutter(i) + line); w > rwidth {
+			rwidth = w
+		}
+	}
+
+	// Columns are separated with two spaces.
+	if width > 0 && width < 2*minColumnWidth+2 {
+		width = 2*minColumnWidth + 2
+	}
+	if width > 0 && lwidth+rwidth+2 > width {
+		avail := width - 2
+		half := avail / 2
+		switch {
+		case lwidth <= half:
+			rwidth = avail - lwidth
+		case rwidth <= avail-half:
+			lwidth = avail - rwidth
+		default:
+			lwidth = half
+			rwidth = avail - half
+		}
+	}
+
+	rows := len(l.lines)
+	if len(r.lines) > rows {
+		rows = len(r.lines)
+	}
+
+	var buf strings.Builder
+	for i := 0; i < rows; i++ {
+		lc := fitLine(l, i, lwidth, elide)
+		rc := fitLine(r, i, rwidth, elide)
+
+		n := len(lc)
+		if len(rc) > n {
+			n = len(rc)
+		}
+		for j := 0; j < n; j++ {
+			var lpart, rpart string
+			if j < len(lc) {
+				lpart = lc[j]
+			}
+			if j < len(rc) {
+				rpart = rc[j]
+			}
+
+			buf.WriteString(lpart)
+			buf.WriteString(strings.Repeat(" ", lwidth-visibleWidth(lpart)+2))
+			buf.WriteString(rpart)
+			buf.WriteString(lineBreak)
+		}
+	}
+
+	return buf.String()
+}
+
+// fitLine splits i-th line of the column into parts fitting into the width.
+func fitLine(c column, i, width int, elide bool) []string {
+	if i >= len(c.lines) {
+		return nil
+	}
+
+	gutter := c.gutter(i)
+	line := c.lines[i]
+	if visibleWidth(gutter+line) <= width {
+		return []string{gutter + line}
+	}
+
+	// Leave a room for the marker.
+	avail := width - visibleWidth(gutter) - 1
+	if avail < 1 {
+		avail = 1
+	}
+
+	parts := splitVisible(line, avail)
+	if elide {
+		return []string{gutter + parts[0] + elideMarker}
+	}
+
+	for j := range parts {
+		if j < len(parts)-1 {
+			parts[j] += wrapMarker
+		}
+		parts[j] = gutter + parts[j]
+	}
+
+	return parts
+}
+
+// splitVisible splits a line into parts having no more than width visible characters each.
+// ANSI escape sequences are not counted and active formatting is carried over to the next part.
+func splitVisible(line string, width int) []string {
+	var res []string
+	var part strings.Builder
+	var active string
+	var count int
+
+	flush := func() {
+		if active != "" {
+			part.WriteString(formatReset)
+		}
+		res = append(res, part.String())
+		part.Reset()
+		part.WriteString(active)
+		count = 0
+	}
+
+	for len(line) > 0 {
+		if loc := leadingANSI(line); loc != nil {
+			seq := line[:loc[1]]
+			part.WriteString(seq)
+			if seq == formatReset {
+				active = ""
+			} else {
+				active += seq
+			}
+			line = line[loc[1]:]
+			continue
+		}
+
+		if count == width {
+			flush()
+		}
+
+		_, size := utf8.DecodeRuneInString(line)
+		part.WriteString(line[:size])
+		count++
+		line = line[size:]
+	}
+
+	res = append(res, part.String())
+	return res
+}
+
+// leadingANSI returns the location of an ANSI escape sequence the line starts with, if any.
+// Lines are only searched when they start with an escape character, the search would take the
+// time proportional to the rest of the line otherwise.
+func leadingANSI(line string) []int {
+	if line[0] != '\x1b' && !strings.HasPrefix(line, "\u009b") {
+		return nil
+	}
+
+	return reLeading.FindStringIndex(line)
+}
+
+var reLeading = regexp.MustCompile("^(?:" + ansi + ")")
+
+// visibleWidth returns the number of visible characters in the line.
+func visibleWidth(line string) int {
+	return utf8.RuneCountInString(stripANSI(line))
+}
@@ -0,0 +1,437 @@
+package deepequal
+
+import (
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"math"
+	"path"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GoLiteral renders the value as a Go expression that can be pasted into a test as
+// an expectation. Types are qualified with import names of the calling *_test.go file
+// and zero struct fields are omitted. Unexported fields of types from other packages
+// cannot be set in literals and are omitted too.
+func GoLiteral(v any) string {
+	return newLiteralWriter().literal(reflect.ValueOf(v))
+}
+
+// ActualLiteral makes SideBySideWith to output the actual value with GoLiteral
+// when it does not match the expected one.
+func ActualLiteral() Option {
+	return func(o *options) {
+		o.actualLiteral = true
+	}
+}
+
+// literalContext is a context of a value in a literal that defines what can be omitted.
+type literalContext int
+
+const (
+	// literalAny is a context with no type information: the root or an interface value.
+	literalAny literalContext = iota
+	// literalField is a context of a known type where untyped constants can be used.
+	literalField
+	// literalElem is a context of slice, array or map elements, where composite types can be elided.
+	literalElem
+)
+
+type literalWriter struct {
+	// local is a path of the package the literal is written for, its types need no qualifiers.
+	local string
+	// imports maps import paths into their names.
+	imports map[string]string
+	stack   map[uintptr]struct{}
+}
+
+func newLiteralWriter() *literalWriter {
+	res := &literalWriter{
+		stack: map[uintptr]struct{}{},
+	}
+	if frame, ok := testFrame(); ok {
+		res.local = framePackage(frame.Function)
+		res.imports = fileImports(frame.File)
+	}
+
+	return res
+}
+
+// literal returns gofmt-ed expression of the value.
+func (w *literalWriter) literal(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+
+	expr := w.value(v, literalAny)
+
+	const prefix = "package p\n\nvar _ = "
+	src, err := format.Source([]byte(prefix + expr))
+	if err != nil {
+		return expr
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(string(src), prefix))
+}
+
+func (w *literalWriter) value(v reflect.Value, ctx literalContext) string {
+	t := v.Type()
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return w.time(v.Interface().(time.Time))
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return w.convert(t, strconv.FormatBool(v.Bool()), ctx, t == reflect.TypeOf(true))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return w.convert(t, strconv.FormatInt(v.Int(), 10), ctx, t == reflect.TypeOf(0))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return w.convert(t, strconv.FormatUint(v.Uint(), 10), ctx, false)
+	case reflect.Float32, reflect.Float64:
+		return w.float(t, v.Float(), ctx)
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		expr := fmt.Sprintf("complex(%s, %s)", w.float(reflect.TypeOf(0.0), real(c), literalField), w.float(reflect.TypeOf(0.0), imag(c), literalField))
+		return w.convert(t, expr, ctx, t == reflect.TypeOf(complex128(0)))
+	case reflect.String:
+		return w.convert(t, strconv.Quote(v.String()), ctx, t == reflect.TypeOf(""))
+
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && v.IsNil() {
+			return w.nilValue(t, ctx)
+		}
+		if isBytes(t) && t.Kind() == reflect.Slice && isText(v.Bytes()) {
+			return fmt.Sprintf("%s(%q)", w.typeName(t), v.Bytes())
+		}
+
+		var buf strings.Builder
+		w.open(&buf, t, ctx, v.Len() > 0 && !isBytes(t))
+		for i := 0; i < v.Len(); i++ {
+			if isBytes(t) {
+				// Binary data is better in hex and is too long to have an element per line.
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				_, _ = fmt.Fprintf(&buf, "0x%02x", v.Index(i).Uint())
+				continue
+			}
+
+			buf.WriteString(w.value(v.Index(i), literalElem))
+			buf.WriteString(",\n")
+		}
+		buf.WriteString("}")
+		return buf.String()
+
+	case reflect.Map:
+		if v.IsNil() {
+			return w.nilValue(t, ctx)
+		}
+
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return compareReflectValues(keys[i], keys[j])
+		})
+
+		var buf strings.Builder
+		w.open(&buf, t, ctx, len(keys) > 0)
+		for _, key := range keys {
+			buf.WriteString(w.value(key, literalElem))
+			buf.WriteString(": ")
+			buf.WriteString(w.value(v.MapIndex(key), literalElem))
+			buf.WriteString(",\n")
+		}
+		buf.WriteString("}")
+		return buf.String()
+
+	case reflect.Struct:
+		var fields []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() && t.PkgPath() != w.local {
+				continue
+			}
+
+			fv := getField(v, i)
+			if fv.IsZero() {
+				continue
+			}
+
+			fields = append(fields, f.Name+": "+w.value(fv, literalField)+",\n")
+		}
+
+		var buf strings.Builder
+		w.open(&buf, t, ctx, len(fields) > 0)
+		for _, field := range fields {
+			buf.WriteString(field)
+		}
+		buf.WriteString("}")
+		return buf.String()
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return w.nilValue(t, ctx)
+		}
+
+		addr := v.Pointer()
+		if _, ok := w.stack[addr]; ok {
+			return "nil /* cycle */"
+		}
+		w.stack[addr] = struct{}{}
+		defer delete(w.stack, addr)
+
+		switch t.Elem().Kind() {
+		case reflect.Slice, reflect.Map:
+			if v.Elem().IsNil() {
+				// Neither &nil nor an elided nil is a pointer to a nil slice or map.
+				return "new(" + w.typeName(t.Elem()) + ")"
+			}
+		}
+
+		switch t.Elem().Kind() {
+		case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+			if t.Elem() == reflect.TypeOf(time.Time{}) {
+				break
+			}
+			if ctx == literalElem {
+				// &T is elided for elements of composite literals.
+				return w.value(v.Elem(), literalElem)
+			}
+			return "&" + w.value(v.Elem(), literalField)
+		}
+
+		// There are no literals for pointers to other values.
+		return fmt.Sprintf("func() %s { v := %s; return &v }()", w.typeName(t), w.value(v.Elem(), literalAny))
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return w.nilValue(t, ctx)
+		}
+
+		return w.value(v.Elem(), literalAny)
+
+	default:
+		if v.IsNil() {
+			return w.nilValue(t, ctx)
+		}
+
+		return fmt.Sprintf("nil /* %s */", w.typeName(t))
+	}
+}
+
+// open writes a type of the composite literal and an opening brace.
+func (w *literalWriter) open(buf *strings.Builder, t reflect.Type, ctx literalContext, multiline bool) {
+	if ctx != literalElem {
+		buf.WriteString(w.typeName(t))
+	}
+	buf.WriteString("{")
+	if multiline {
+		buf.WriteString("\n")
+	}
+}
+
+// convert wraps the constant expression with a conversion when its type cannot be inferred.
+func (w *literalWriter) convert(t reflect.Type, expr string, ctx literalContext, isDefault bool) string {
+	if ctx != literalAny || isDefault {
+		return expr
+	}
+
+	return w.typeName(t) + "(" + expr + ")"
+}
+
+func (w *literalWriter) float(t reflect.Type, f float64, ctx literalContext) string {
+	var expr string
+	switch {
+	case math.IsNaN(f):
+		expr = w.qualifier("math", "math") + "NaN()"
+	case math.IsInf(f, 1):
+		expr = w.qualifier("math", "math") + "Inf(1)"
+	case math.IsInf(f, -1):
+		expr = w.qualifier("math", "math") + "Inf(-1)"
+	default:
+		bits := 64
+		if t.Kind() == reflect.Float32 {
+			bits = 32
+		}
+		expr = strconv.FormatFloat(f, 'g', -1, bits)
+		if !strings.ContainsAny(expr, ".e") {
+			// Keep it a floating point constant.
+			expr += ".0"
+		}
+
+		return w.convert(t, expr, ctx, t == reflect.TypeOf(0.0))
+	}
+
+	if t == reflect.TypeOf(0.0) {
+		return expr
+	}
+
+	// Functions of math return float64 values, they need conversions to other types.
+	return w.typeName(t) + "(" + expr + ")"
+}
+
+func (w *literalWriter) nilValue(t reflect.Type, ctx literalContext) string {
+	if ctx != literalAny || t.Kind() == reflect.Interface {
+		return "nil"
+	}
+
+	return "(" + w.typeName(t) + ")(nil)"
+}
+
+func (w *literalWriter) time(t time.Time) string {
+	q := w.qualifier("time", "time")
+
+	var loc string
+	switch t.Location() {
+	case time.UTC:
+		loc = q + "UTC"
+	case time.Local:
+		loc = q + "Local"
+	default:
+		name, offset := t.Zone()
+		loc = fmt.Sprintf("%sFixedZone(%q, %d)", q, name, offset)
+	}
+
+	return fmt.Sprintf(
+		"%sDate(%d, %s%s, %d, %d, %d, %d, %d, %s)",
+		q, t.Year(), q, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc,
+	)
+}
+
+// typeName returns a qualified name of the type.
+func (w *literalWriter) typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		if t == reflect.TypeOf(byte(0)) {
+			return "byte"
+		}
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+
+		// Names of package level types are prefixed with package names.
+		pkg := t.String()
+		if pos := strings.IndexByte(pkg, '.'); pos >= 0 {
+			pkg = pkg[:pos]
+		}
+
+		return w.qualifier(t.PkgPath(), pkg) + t.Name()
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + w.typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + w.typeName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), w.typeName(t.Elem()))
+	case reflect.Map:
+		return "map[" + w.typeName(t.Key()) + "]" + w.typeName(t.Elem())
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "any"
+		}
+	case reflect.Struct:
+		var buf strings.Builder
+		buf.WriteString("struct {")
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if i > 0 {
+				buf.WriteString(";")
+			}
+			buf.WriteString(" ")
+			if !f.Anonymous {
+				buf.WriteString(f.Name + " ")
+			}
+			buf.WriteString(w.typeName(f.Type))
+			if f.Tag != "" {
+				buf.WriteString(" " + strconv.Quote(string(f.Tag)))
+			}
+		}
+		buf.WriteString(" }")
+		return buf.String()
+	}
+
+	return t.String()
+}
+
+// qualifier returns a prefix for names of the package with the given path and name.
+func (w *literalWriter) qualifier(pkgPath, name string) string {
+	if pkgPath == w.local {
+		return ""
+	}
+
+	switch alias, ok := w.imports[pkgPath]; {
+	case !ok || alias == "_":
+		return name + "."
+	case alias == ".":
+		return ""
+	default:
+		return alias + "."
+	}
+}
+
+// testFrame returns the first call frame made in some *_test.go file.
+func testFrame() (runtime.Frame, bool) {
+	pcs := make([]uintptr, 128)
+	n := runtime.Callers(0, pcs)
+	if n == 0 {
+		return runtime.Frame{}, false
+	}
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if strings.HasSuffix(frame.File, "_test.go") {
+			return frame, true
+		}
+
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// framePackage returns the package path of the function name got from the call frame.
+func framePackage(function string) string {
+	dir, name := path.Split(function)
+	if pos := strings.IndexByte(name, '.'); pos >= 0 {
+		name = name[:pos]
+	}
+
+	return dir + name
+}
+
+var importsCache sync.Map
+
+// fileImports returns explicit import names of the Go source file by import paths.
+// Packages imported with no names are referred by their own names.
+func fileImports(file string) map[string]string {
+	if res, ok := importsCache.Load(file); ok {
+		return res.(map[string]string)
+	}
+
+	res := map[string]string{}
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+	if err == nil {
+		for _, imp := range f.Imports {
+			importPath, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+
+			if imp.Name != nil {
+				res[importPath] = imp.Name.Name
+			}
+		}
+	}
+
+	importsCache.Store(file, res)
+	return res
+}
@@ -0,0 +1,140 @@
+package deepequal_test
+
+import (
+	"go/parser"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sirkon/deepequal"
+	td "github.com/sirkon/deepequal/internal/testdata"
+)
+
+type literalKind int
+
+func TestGoLiteral(t *testing.T) {
+	type sample struct {
+		Name     string
+		Count    *int64
+		Tags     []string
+		Attrs    map[string]any
+		When     time.Time
+		Envelope *td.Envelope
+		Data     []byte
+		Kind     literalKind
+		Children []*sample
+		hidden   int
+	}
+
+	count := int64(5)
+	value := sample{
+		Name:  "x",
+		Count: &count,
+		Tags:  []string{"a", "b"},
+		Attrs: map[string]any{"b": 1, "a": int32(2), "c": nil},
+		When:  time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		Envelope: &td.Envelope{
+			Id:      proto.String("1"),
+			Payload: &td.Envelope_Item{Item: &td.Item{Name: "apple"}},
+		},
+		Data:     []byte{0, 1, 0xff},
+		Kind:     3,
+		Children: []*sample{{Name: "child"}},
+		hidden:   1,
+	}
+
+	const want = `sample{
+	Name:  "x",
+	Count: func() *int64 { v := int64(5); return &v }(),
+	Tags: []string{
+		"a",
+		"b",
+	},
+	Attrs: map[string]any{
+		"a": int32(2),
+		"b": 1,
+		"c": nil,
+	},
+	When: time.Date(2021, time.January, 2, 3, 4, 5, 0, time.UTC),
+	Envelope: &td.Envelope{
+		Id: func() *string { v := "1"; return &v }(),
+		Payload: &td.Envelope_Item{
+			Item: &td.Item{
+				Name: "apple",
+			},
+		},
+	},
+	Data: []byte{0x00, 0x01, 0xff},
+	Kind: 3,
+	Children: []*sample{
+		{
+			Name: "child",
+		},
+	},
+	hidden: 1,
+}`
+	got := deepequal.GoLiteral(value)
+	if got != want {
+		t.Errorf("unexpected literal\n%s\nwant\n%s", got, want)
+	}
+	if _, err := parser.ParseExpr(got); err != nil {
+		t.Errorf("literal must be a valid expression: %s", err)
+	}
+
+	type pointers struct {
+		P *[]int
+		M *map[string]int
+	}
+	var nilSlice []int
+	var nilMap map[string]int
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{
+			name:  "nil",
+			value: nil,
+			want:  "nil",
+		},
+		{
+			name:  "typed constant",
+			value: int32(5),
+			want:  "int32(5)",
+		},
+		{
+			name:  "float",
+			value: 1.0,
+			want:  "1.0",
+		},
+		{
+			name:  "nil slice",
+			value: []int(nil),
+			want:  "([]int)(nil)",
+		},
+		{
+			name:  "text bytes",
+			value: []byte("hello"),
+			want:  `[]byte("hello")`,
+		},
+		{
+			name:  "pointers to nil slice and map",
+			value: pointers{P: &nilSlice, M: &nilMap},
+			want:  "pointers{\n\tP: new([]int),\n\tM: new(map[string]int),\n}",
+		},
+		{
+			name:  "pointer to nil slice element",
+			value: map[string]*[]int{"a": &nilSlice},
+			want:  "map[string]*[]int{\n\t\"a\": new([]int),\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deepequal.GoLiteral(tt.value); got != tt.want {
+				t.Errorf("unexpected literal %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
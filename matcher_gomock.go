@@ -3,36 +3,205 @@ package deepequal
 import (
 	"fmt"
 	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
 )
 
-// NewEqMatcher creates equality matcher.
-func NewEqMatcher(v any) EqMatcher {
-	return EqMatcher{v: v}
+// TypeMatching is a set of rules EqMatcher uses to match arguments of types different
+// from the expected value type.
+type TypeMatching uint
+
+const (
+	// MatchStrict accepts arguments of the exact type of the expected value only.
+	MatchStrict TypeMatching = 0
+	// MatchAssignable accepts arguments assignable to the expected value type and
+	// arguments the expected value is assignable to.
+	MatchAssignable TypeMatching = 1 << 0
+	// MatchConvertible accepts arguments convertible to the expected value type and back, like
+	// a named string type for a string or int64 for int. Values must survive conversions.
+	MatchConvertible TypeMatching = 1 << 1
+	// MatchInterfaces unwraps interfaces gomock passes arguments with: a nil interface
+	// matches typed nil pointers, maps, slices, etc. and vice versa.
+	MatchInterfaces TypeMatching = 1 << 2
+	// MatchProto accepts protobuf messages of other Go types with the same full name,
+	// like a message generated with the legacy APIv1 generator for the APIv2 one.
+	MatchProto TypeMatching = 1 << 3
+
+	// MatchDefault is used when no MatchTypes option is given.
+	MatchDefault = MatchAssignable | MatchInterfaces | MatchProto
+)
+
+// MatchTypes sets rules EqMatcher uses to match arguments of types different from the
+// expected value type. Has no effect on comparisons of other functions.
+func MatchTypes(rules TypeMatching) Option {
+	return func(o *options) {
+		o.typeMatching = &rules
+	}
+}
+
+// NewEqMatcher creates equality matcher. Options tune the comparison just like for EqualWith.
+// Matchers are comparable, ones created with options are only equal to their copies though.
+func NewEqMatcher(v any, opts ...Option) EqMatcher {
+	res := EqMatcher{v: v}
+	if len(opts) > 0 {
+		res.opts = newOptions(opts)
+	}
+
+	return res
 }
 
-// EqMatcher equality matcher for gomock. Implements gomock.Matcher.
+// EqMatcher equality matcher for gomock. Implements gomock.Matcher and gomock.GotFormatter,
+// so mismatched calls show the difference between expected and actual arguments.
 type EqMatcher struct {
-	v any
+	v    any
+	opts *options
 }
 
 // Matches to satisfy gomock.Matcher
 func (e EqMatcher) Matches(x any) bool {
-	if e.v == nil || x == nil {
-		return Equal(e.v, x)
+	want, got, err := e.convert(x)
+	if err != nil {
+		return false
+	}
+
+	o := e.options()
+	wv := reflect.ValueOf(want)
+	return equalValues(wv, reflect.ValueOf(got), o, o.pathFor(wv))
+}
+
+// String to satisfy gomock.Matcher
+func (e EqMatcher) String() string {
+	return e.render(e.v)
+}
+
+// Got to satisfy gomock.GotFormatter. Gives the actual value followed by
+// differences with the expected one, a difference per line, or the reason
+// why its type is incompatible.
+func (e EqMatcher) Got(x any) string {
+	res := e.render(x)
+
+	want, got, err := e.convert(x)
+	if err != nil {
+		return res + "\ndifference: " + err.Error()
+	}
+
+	diffs := reportValues(reflect.ValueOf(want), reflect.ValueOf(got), e.options())
+	if len(diffs) == 0 {
+		return res
 	}
 
+	return res + "\ndifference:\n    " + strings.ReplaceAll(diffs.String(), "\n", "\n    ")
+}
+
+func (e EqMatcher) options() *options {
+	if e.opts == nil {
+		return newOptions(nil)
+	}
+
+	return e.opts
+}
+
+// convert brings the expected value and x to the same type according to type matching rules.
+func (e EqMatcher) convert(x any) (any, any, error) {
+	rules := e.options().matching()
 	a := reflect.ValueOf(e.v)
 	b := reflect.ValueOf(x)
 
-	if a.Type().AssignableTo(b.Type()) {
-		aConv := a.Convert(b.Type())
-		return Equal(aConv.Interface(), b.Interface())
+	switch {
+	case !a.IsValid() || !b.IsValid():
+		if rules&MatchInterfaces != 0 && isNilValue(a) && isNilValue(b) {
+			return nil, nil, nil
+		}
+
+		return e.v, x, nil
+	case a.Type() == b.Type():
+		return e.v, x, nil
 	}
 
-	return false
+	at := a.Type()
+	bt := b.Type()
+	if rules&MatchAssignable != 0 {
+		if at.AssignableTo(bt) {
+			return a.Convert(bt).Interface(), x, nil
+		}
+		if bt.AssignableTo(at) {
+			return e.v, b.Convert(at).Interface(), nil
+		}
+	}
+
+	if rules&MatchConvertible != 0 && at.ConvertibleTo(bt) && bt.ConvertibleTo(at) {
+		got := b.Convert(at)
+		if !equalValues(got.Convert(bt), b, e.options(), valuePath{}) {
+			// The value does not survive conversions, so it is different anyway.
+			return e.v, x, nil
+		}
+
+		return e.v, got.Interface(), nil
+	}
+
+	if rules&MatchProto != 0 {
+		if got, ok, err := convertProto(a, b); ok {
+			return e.v, got, err
+		}
+	}
+
+	return nil, nil, fmt.Errorf("incompatible types: want %s, got %s", at, bt)
 }
 
-// String to satisfy gomock.Matcher
-func (e EqMatcher) String() string {
-	return fmt.Sprintf("%v", e.v)
+// convertProto converts the protobuf message b to the type of a if both are messages
+// of the same full name.
+func convertProto(a, b reflect.Value) (any, bool, error) {
+	am, ok := a.Interface().(proto.Message)
+	if !ok || a.Kind() != reflect.Pointer {
+		return nil, false, nil
+	}
+	bm, ok := b.Interface().(proto.Message)
+	if !ok {
+		return nil, false, nil
+	}
+
+	name := proto.MessageV2(am).ProtoReflect().Descriptor().FullName()
+	if name != proto.MessageV2(bm).ProtoReflect().Descriptor().FullName() {
+		return nil, false, nil
+	}
+
+	data, err := proto.Marshal(bm)
+	if err != nil {
+		return nil, true, fmt.Errorf("marshal %s: %w", b.Type(), err)
+	}
+
+	res := reflect.New(a.Type().Elem()).Interface().(proto.Message)
+	if err := proto.Unmarshal(data, res); err != nil {
+		return nil, true, fmt.Errorf("convert %s to %s: %w", b.Type(), a.Type(), err)
+	}
+
+	return res, true, nil
+}
+
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// render renders the value as a Go-like value tree with no highlighting.
+func (e EqMatcher) render(x any) string {
+	if x == nil {
+		return "nil"
+	}
+
+	o := *e.options()
+	o.colors = ColorNever
+
+	v := reflect.ValueOf(x)
+	p := newPrinter(true, &o)
+	p.printValue("", v, o.pathFor(v), nil, false, true, map[uintptr]struct{}{})
+
+	return strings.ReplaceAll(p.buf.String(), "\r", "")
 }
@@ -0,0 +1,264 @@
+package deepequal_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirkon/deepequal"
+	"github.com/sirkon/deepequal/internal/testdata"
+)
+
+func TestEqMatcher(t *testing.T) {
+	m := deepequal.NewEqMatcher(&testdata.Item{Name: "a", Price: 1, Weight: 0.5})
+	if !m.Matches(&testdata.Item{Name: "a", Price: 1, Weight: 0.5}) {
+		t.Error("equal messages must match")
+	}
+	if m.Matches(&testdata.Item{Name: "b", Price: 1, Weight: 0.5}) {
+		t.Error("different messages must not match")
+	}
+
+	wantString := `&testdata.Item{
+  Name: "a",
+  Price: 1,
+  Weight: 0.5,
+  Discount: 0,
+}`
+	if m.String() != wantString {
+		t.Errorf("unexpected description:\n%s", m.String())
+	}
+
+	wantGot := `&testdata.Item{
+  Name: "b",
+  Price: 2,
+  Weight: 0.5,
+  Discount: 0,
+}
+difference:
+    .name: want "a", got "b"
+    .price: want 1, got 2`
+	if got := m.Got(&testdata.Item{Name: "b", Price: 2, Weight: 0.5}); got != wantGot {
+		t.Errorf("unexpected actual value:\n%s", got)
+	}
+
+	if got := m.Got(12); got != "int(12)\ndifference: incompatible types: want *testdata.Item, got int" {
+		t.Errorf("unexpected actual value of a different type:\n%s", got)
+	}
+	if m.Matches(12) || m.String() != wantString {
+		t.Errorf("the description must not depend on matched values:\n%s", m.String())
+	}
+
+	if deepequal.NewEqMatcher(1) != deepequal.NewEqMatcher(1) {
+		t.Error("matchers without options must be comparable by values")
+	}
+	if c := m; c != m {
+		t.Error("a matcher must be equal to its copy")
+	}
+
+	m = deepequal.NewEqMatcher(&testdata.Item{Name: "a", Weight: 0.5}, deepequal.FloatAbsTolerance(0.1))
+	if !m.Matches(&testdata.Item{Name: "a", Weight: 0.55}) {
+		t.Error("options must be applied")
+	}
+	if got := m.Got(&testdata.Item{Name: "a", Weight: 0.55}); got != "&testdata.Item{\n  Name: \"a\",\n  Price: 0,\n  Weight: 0.55,\n  Discount: 0,\n}" {
+		t.Errorf("no difference must be shown for matching values:\n%s", got)
+	}
+
+	m = deepequal.NewEqMatcher(nil)
+	if m.String() != "nil" || !m.Matches(nil) || m.Matches(1) {
+		t.Error("unexpected nil matching")
+	}
+}
+
+func TestEqMatcherFuncsAndChans(t *testing.T) {
+	type handler struct {
+		Name string
+		Call func()
+	}
+
+	ch := make(chan int)
+	m := deepequal.NewEqMatcher(ch)
+	if !m.Matches(ch) || m.Matches(make(chan int)) {
+		t.Error("channels must be matched by identity")
+	}
+	if want := fmt.Sprintf("(chan int)(%p)", ch); m.String() != want {
+		t.Errorf("unexpected description of a channel %q, want %q", m.String(), want)
+	}
+	if got := m.Got(make(chan int)); !strings.HasPrefix(got, "(chan int)(0x") || !strings.Contains(got, "\ndifference:\n") {
+		t.Errorf("unexpected actual channel %q", got)
+	}
+
+	m = deepequal.NewEqMatcher(handler{Name: "a", Call: func() {}})
+	if m.Matches(handler{Name: "a", Call: func() {}}) {
+		t.Error("non-nil functions must not match")
+	}
+	if !strings.HasPrefix(m.String(), "deepequal_test.handler{\n  Name: \"a\",\n  Call: (func())(0x") {
+		t.Errorf("unexpected description of a function %q", m.String())
+	}
+	wantGot := "deepequal_test.handler{\n  Name: \"b\",\n  Call: (func())(nil),\n}\ndifference:\n    .Call: want 0x"
+	if got := m.Got(handler{Name: "b"}); !strings.HasPrefix(got, wantGot) {
+		t.Errorf("unexpected actual function %q", got)
+	}
+}
+
+func TestEqMatcherTypes(t *testing.T) {
+	type name string
+	type names []string
+
+	tests := []struct {
+		name  string
+		want  any
+		got   any
+		rules []deepequal.Option
+		match bool
+		err   string
+	}{
+		{
+			name:  "same-types",
+			want:  []string{"a"},
+			got:   []string{"a"},
+			rules: []deepequal.Option{deepequal.MatchTypes(deepequal.MatchStrict)},
+			match: true,
+		},
+		{
+			name:  "strict",
+			want:  names{"a"},
+			got:   []string{"a"},
+			rules: []deepequal.Option{deepequal.MatchTypes(deepequal.MatchStrict)},
+			err:   "incompatible types: want deepequal_test.names, got []string",
+		},
+		{
+			name:  "assignable-to-argument",
+			want:  []string{"a"},
+			got:   names{"a"},
+			match: true,
+		},
+		{
+			name:  "assignable-to-expected",
+			want:  names{"a"},
+			got:   []string{"a"},
+			match: true,
+		},
+		{
+			name:  "assignable-mismatch",
+			want:  names{"a"},
+			got:   []string{"b"},
+			match: false,
+		},
+		{
+			name: "not-assignable",
+			want: name("a"),
+			got:  "a",
+			err:  "incompatible types: want deepequal_test.name, got string",
+		},
+		{
+			name:  "convertible",
+			want:  name("a"),
+			got:   "a",
+			rules: []deepequal.Option{deepequal.MatchTypes(deepequal.MatchConvertible)},
+			match: true,
+		},
+		{
+			name:  "convertible-lossy",
+			want:  int8(0),
+			got:   int64(256),
+			rules: []deepequal.Option{deepequal.MatchTypes(deepequal.MatchConvertible)},
+			match: false,
+		},
+		{
+			name:  "convertible-numbers",
+			want:  1,
+			got:   1.0,
+			rules: []deepequal.Option{deepequal.MatchTypes(deepequal.MatchConvertible)},
+			match: true,
+		},
+		{
+			name:  "convertible-fraction",
+			want:  1,
+			got:   1.5,
+			rules: []deepequal.Option{deepequal.MatchTypes(deepequal.MatchConvertible)},
+			match: false,
+		},
+		{
+			name:  "not-convertible",
+			want:  1,
+			got:   []int{1},
+			rules: []deepequal.Option{deepequal.MatchTypes(deepequal.MatchConvertible)},
+			err:   "incompatible types: want int, got []int",
+		},
+		{
+			name:  "nil-interface",
+			want:  (*testdata.Item)(nil),
+			got:   nil,
+			match: true,
+		},
+		{
+			name:  "nil-interface-strict",
+			want:  (*testdata.Item)(nil),
+			got:   nil,
+			rules: []deepequal.Option{deepequal.MatchTypes(deepequal.MatchStrict)},
+			match: false,
+		},
+		{
+			name:  "nil-interface-non-nil",
+			want:  []string{},
+			got:   nil,
+			match: false,
+		},
+		{
+			name:  "proto-legacy",
+			want:  &testdata.Item{Name: "a", Price: 12},
+			got:   &legacyItem{Name: "a", Price: 12},
+			match: true,
+		},
+		{
+			name:  "proto-to-legacy",
+			want:  &legacyItem{Name: "a", Price: 12},
+			got:   &testdata.Item{Name: "a", Price: 13},
+			match: false,
+		},
+		{
+			name:  "proto-strict",
+			want:  &testdata.Item{Name: "a", Price: 12},
+			got:   &legacyItem{Name: "a", Price: 12},
+			rules: []deepequal.Option{deepequal.MatchTypes(deepequal.MatchAssignable)},
+			err:   "incompatible types: want *testdata.Item, got *deepequal_test.legacyItem",
+		},
+		{
+			name: "proto-different-messages",
+			want: &testdata.Item{Name: "a"},
+			got:  &testdata.Sub{Val: 1},
+			err:  "incompatible types: want *testdata.Item, got *testdata.Sub",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := deepequal.NewEqMatcher(tt.want, tt.rules...)
+			if match := m.Matches(tt.got); match != (tt.match && tt.err == "") {
+				t.Errorf("unexpected match result %v", match)
+			}
+
+			var err string
+			if _, e, ok := strings.Cut(m.Got(tt.got), "\ndifference: "); ok {
+				err = e
+			}
+			if err != tt.err {
+				t.Errorf("unexpected error in the actual value description %q, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+// legacyItem mimics testdata.Item generated with the legacy APIv1 generator.
+type legacyItem struct {
+	Name     string  `protobuf:"bytes,1,opt,name=name,proto3"`
+	Price    int64   `protobuf:"varint,2,opt,name=price,proto3"`
+	Weight   float64 `protobuf:"fixed64,3,opt,name=weight,proto3"`
+	Discount float32 `protobuf:"fixed32,4,opt,name=discount,proto3"`
+}
+
+func (m *legacyItem) Reset()         { *m = legacyItem{} }
+func (m *legacyItem) String() string { return fmt.Sprintf("%+v", *m) }
+func (*legacyItem) ProtoMessage()    {}
+
+func (*legacyItem) XXX_MessageName() string { return "sample.Item" }
@@ -0,0 +1,185 @@
+package deepequal
+
+// sequenceDiff finds a shortest edit script between two sequences of lengths n and m
+// with Myers' O(ND) algorithm in its linear space variant. Elements are compared by
+// their indices with eq. Returns flags of left elements missing on the right and
+// right elements missing on the left.
+//
+// The search of each middle snake is bounded, so really different huge inputs
+// get a correct, but not necessarily the shortest, edit script in a reasonable time.
+func sequenceDiff(n, m int, eq func(i, j int) bool) (left, right []bool) {
+	size := n + m + 2
+	d := &myers{
+		eq:    eq,
+		left:  make([]bool, n),
+		right: make([]bool, m),
+		vf:    make([]int, 2*size+1),
+		vb:    make([]int, 2*size+1),
+		off:   size,
+		limit: myersCostLimit(n + m),
+	}
+	d.compare(0, n, 0, m)
+
+	return d.left, d.right
+}
+
+// myersCostLimit computes a maximal edit cost the middle snake is looked for.
+func myersCostLimit(total int) int {
+	limit := 1
+	for limit*limit < total {
+		limit <<= 1
+	}
+
+	// Small inputs are always diffed precisely.
+	if limit < 256 {
+		return 256
+	}
+
+	return limit
+}
+
+type myers struct {
+	eq    func(i, j int) bool
+	left  []bool
+	right []bool
+
+	// vf and vb are furthest reaching points of forward and backward searches
+	// on diagonals shifted by off.
+	vf  []int
+	vb  []int
+	off int
+
+	limit int
+}
+
+// compare diffs x0:x1 and y0:y1 subsequences.
+func (d *myers) compare(x0, x1, y0, y1 int) {
+	for x0 < x1 && y0 < y1 && d.eq(x0, y0) {
+		x0++
+		y0++
+	}
+	for x0 < x1 && y0 < y1 && d.eq(x1-1, y1-1) {
+		x1--
+		y1--
+	}
+
+	switch {
+	case x0 == x1:
+		for y := y0; y < y1; y++ {
+			d.right[y] = true
+		}
+	case y0 == y1:
+		for x := x0; x < x1; x++ {
+			d.left[x] = true
+		}
+	default:
+		xs, ys, xe, ye, ok := d.middleSnake(x0, x1, y0, y1)
+		if !ok {
+			// Subsequences are too different, just replace one with another.
+			for x := x0; x < x1; x++ {
+				d.left[x] = true
+			}
+			for y := y0; y < y1; y++ {
+				d.right[y] = true
+			}
+			return
+		}
+
+		d.compare(x0, xs, y0, ys)
+		d.compare(xe, x1, ye, y1)
+	}
+}
+
+// middleSnake finds a snake in the middle of the shortest edit script of the
+// x0:x1 and y0:y1 subsequences. These must not be empty and their first and
+// last elements must differ. Returns snake bounds: (xs, ys) - (xe, ye) and false
+// if the search was too expensive and there's no good enough point to split on.
+func (d *myers) middleSnake(x0, x1, y0, y1 int) (xs, ys, xe, ye int, ok bool) {
+	n := x1 - x0
+	m := y1 - y0
+	delta := n - m
+	odd := delta&1 != 0
+
+	// Forward search is held in a, b coordinates relative to (x0, y0), diagonal k = a - b.
+	// Backward search uses the same coordinates over reversed subsequences,
+	// so its diagonal k matches delta - k diagonal of the forward search.
+	vf, vb, off := d.vf, d.vb, d.off
+	vf[off+1] = 0
+	vb[off+1] = 0
+
+	for c := 0; ; c++ {
+		if c > d.limit {
+			return d.approximateSplit(x0, x1, y0, y1, c-1)
+		}
+
+		for k := -c; k <= c; k += 2 {
+			var a int
+			if k == -c || (k != c && vf[off+k-1] < vf[off+k+1]) {
+				a = vf[off+k+1]
+			} else {
+				a = vf[off+k-1] + 1
+			}
+			b := a - k
+			sa, sb := a, b
+			for a < n && b < m && d.eq(x0+a, y0+b) {
+				a++
+				b++
+			}
+			vf[off+k] = a
+
+			if kb := delta - k; odd && kb >= -(c-1) && kb <= c-1 && a+vb[off+kb] >= n {
+				return x0 + sa, y0 + sb, x0 + a, y0 + b, true
+			}
+		}
+
+		for k := -c; k <= c; k += 2 {
+			var a int
+			if k == -c || (k != c && vb[off+k-1] < vb[off+k+1]) {
+				a = vb[off+k+1]
+			} else {
+				a = vb[off+k-1] + 1
+			}
+			b := a - k
+			sa, sb := a, b
+			for a < n && b < m && d.eq(x1-a-1, y1-b-1) {
+				a++
+				b++
+			}
+			vb[off+k] = a
+
+			if kf := delta - k; !odd && kf >= -c && kf <= c && a+vf[off+kf] >= n {
+				return x1 - a, y1 - b, x1 - sa, y1 - sb, true
+			}
+		}
+	}
+}
+
+// approximateSplit chooses a split point when the middle snake is too expensive
+// to look for: the furthest reaching point of the forward search with c edits.
+// The point is only good when the search went along diagonals substantially,
+// i.e. at least a half of c steps were matches.
+func (d *myers) approximateSplit(x0, x1, y0, y1 int, c int) (xs, ys, xe, ye int, ok bool) {
+	n := x1 - x0
+	m := y1 - y0
+
+	best := 2 * c
+	var ba, bb int
+	for k := -c; k <= c; k += 2 {
+		a := d.vf[d.off+k]
+		b := a - k
+		if a > n || b > m || b < 0 || a+b == n+m {
+			continue
+		}
+
+		if a+b > best {
+			best = a + b
+			ba, bb = a, b
+		}
+	}
+
+	if best == 2*c {
+		return 0, 0, 0, 0, false
+	}
+
+	return x0 + ba, y0 + bb, x0 + ba, y0 + bb, true
+}
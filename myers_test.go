@@ -0,0 +1,156 @@
+package deepequal
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSequenceDiff(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 2000; i++ {
+		x := randomSequence(rnd, rnd.Intn(30), 4)
+		y := randomSequence(rnd, rnd.Intn(30), 4)
+
+		left, right := sequenceDiff(len(x), len(y), func(i, j int) bool {
+			return x[i] == y[j]
+		})
+		checkEditScript(t, x, y, left, right)
+
+		if got, want := len(x)-count(left), lcsLength(x, y); got != want {
+			t.Fatalf("edit script of %v and %v is not the shortest: common %d, want %d", x, y, got, want)
+		}
+	}
+}
+
+func TestSequenceDiffLimited(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+
+	x := randomSequence(rnd, 20000, 1000)
+	y := randomSequence(rnd, 15000, 1000)
+	left, right := sequenceDiff(len(x), len(y), func(i, j int) bool {
+		return x[i] == y[j]
+	})
+	checkEditScript(t, x, y, left, right)
+
+	// Many sparse changes exceed the cost limit, but still must be found precisely enough.
+	x = randomSequence(rnd, 20000, 1<<30)
+	y = append([]int(nil), x...)
+	for i := 0; i < 500; i++ {
+		y[rnd.Intn(len(y))] = -1
+	}
+	left, right = sequenceDiff(len(x), len(y), func(i, j int) bool {
+		return x[i] == y[j]
+	})
+	checkEditScript(t, x, y, left, right)
+	if changes := count(left) + count(right); changes > 2000 {
+		t.Errorf("too many changes found: %d", changes)
+	}
+}
+
+func BenchmarkOrderedDifference(b *testing.B) {
+	rnd := rand.New(rand.NewSource(3))
+	type item struct {
+		ID   int
+		Name string
+	}
+
+	x := make([]item, 10000)
+	for i := range x {
+		x[i] = item{ID: i, Name: "item"}
+	}
+
+	// A few local changes: the most common case for tests.
+	changed := append([]item(nil), x...)
+	for i := 0; i < 20; i++ {
+		changed[rnd.Intn(len(changed))].Name = "changed"
+	}
+	changed = append(changed[:5000], changed[5010:]...)
+
+	// Totally different values: the worst case.
+	different := make([]item, 10000)
+	for i := range different {
+		different[i] = item{ID: rnd.Intn(len(x)), Name: "item"}
+	}
+
+	opts := newOptions(nil)
+	for _, bb := range []struct {
+		name string
+		y    []item
+	}{
+		{name: "few changes", y: changed},
+		{name: "totally different", y: different},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			l := reflect.ValueOf(x)
+			r := reflect.ValueOf(bb.y)
+			for i := 0; i < b.N; i++ {
+				orderedDifference(l, r, opts, valuePath{})
+			}
+		})
+	}
+}
+
+// checkEditScript checks if elements having no flags are the same sequence on both sides.
+func checkEditScript(t *testing.T, x, y []int, left, right []bool) {
+	t.Helper()
+
+	var cx, cy []int
+	for i, v := range x {
+		if !left[i] {
+			cx = append(cx, v)
+		}
+	}
+	for i, v := range y {
+		if !right[i] {
+			cy = append(cy, v)
+		}
+	}
+
+	if !reflect.DeepEqual(cx, cy) {
+		t.Fatalf("invalid edit script of %v and %v: common parts %v and %v differ", x, y, cx, cy)
+	}
+}
+
+func lcsLength(x, y []int) int {
+	dp := make([][]int, len(x)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(y)+1)
+	}
+
+	for i := 1; i <= len(x); i++ {
+		for j := 1; j <= len(y); j++ {
+			switch {
+			case x[i-1] == y[j-1]:
+				dp[i][j] = dp[i-1][j-1] + 1
+			case dp[i-1][j] > dp[i][j-1]:
+				dp[i][j] = dp[i-1][j]
+			default:
+				dp[i][j] = dp[i][j-1]
+			}
+		}
+	}
+
+	return dp[len(x)][len(y)]
+}
+
+func randomSequence(rnd *rand.Rand, n, alphabet int) []int {
+	res := make([]int, n)
+	for i := range res {
+		res[i] = rnd.Intn(alphabet)
+	}
+
+	return res
+}
+
+func count(flags []bool) int {
+	var res int
+	for _, f := range flags {
+		if f {
+			res++
+		}
+	}
+
+	return res
+}
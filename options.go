@@ -0,0 +1,65 @@
+package deepequal
+
+import "reflect"
+
+// Option tunes the comparison and the rendering of differences.
+type Option func(*options)
+
+// options is a set of knobs collected from Option values.
+type options struct {
+	comparers      map[reflect.Type]comparer
+	noEqualMethods bool
+	ignorePaths    []pathPattern
+	ignoreFuncs    []func(reflect.StructField) bool
+	unorderedAll   bool
+	unorderedPaths []pathPattern
+	unorderedTypes map[reflect.Type]struct{}
+	floats         floatOptions
+	proto          protoOptions
+	equateEmpty    bool
+	colors         ColorMode
+	unified        bool
+	maxWidth       int
+	elide          bool
+	fold           bool
+	foldContext    int
+	protoText      bool
+	actualLiteral  bool
+	typeMatching   *TypeMatching
+}
+
+func newOptions(opts []Option) *options {
+	res := &options{}
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	return res
+}
+
+// matching returns type matching rules of EqMatcher.
+func (o *options) matching() TypeMatching {
+	if o.typeMatching == nil {
+		return MatchDefault
+	}
+
+	return *o.typeMatching
+}
+
+// pathFor returns a path for the root value. The path is only tracked
+// when there are options depending on it.
+func (o *options) pathFor(v reflect.Value) valuePath {
+	if !v.IsValid() || len(o.ignorePaths)+len(o.unorderedPaths) == 0 {
+		return valuePath{}
+	}
+
+	return newPath(v.Type())
+}
+
+// protoWalk checks if protobuf messages cannot be compared with proto.Equal
+// because of options affecting them.
+func (o *options) protoWalk() bool {
+	return len(o.ignorePaths) > 0 ||
+		o.unorderedAll || len(o.unorderedPaths) > 0 || len(o.unorderedTypes) > 0 ||
+		o.floats.custom() || o.proto.custom()
+}
@@ -0,0 +1,197 @@
+package deepequal
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// valuePath is a path to a value from the root of compared entities.
+// Steps are rendered as Go selectors and index expressions: .Field, [3], ["key"].
+// Pointers and interfaces are dereferenced transparently and do not make their own steps.
+type valuePath struct {
+	root  reflect.Type
+	steps []string
+}
+
+// newPath creates a path for a root value of the given type.
+func newPath(root reflect.Type) valuePath {
+	for root != nil && root.Kind() == reflect.Pointer {
+		root = root.Elem()
+	}
+
+	return valuePath{root: root}
+}
+
+// tracked checks if the path is actually tracked. Untracked paths are
+// cheap to pass around when nothing depends on them.
+func (p valuePath) tracked() bool {
+	return p.root != nil
+}
+
+func (p valuePath) field(name string) valuePath {
+	if !p.tracked() {
+		return p
+	}
+
+	return p.step("." + name)
+}
+
+func (p valuePath) index(i int) valuePath {
+	if !p.tracked() {
+		return p
+	}
+
+	return p.step("[" + strconv.Itoa(i) + "]")
+}
+
+func (p valuePath) key(k reflect.Value) valuePath {
+	if !p.tracked() {
+		return p
+	}
+
+	return p.step("[" + formatKey(k) + "]")
+}
+
+func (p valuePath) step(s string) valuePath {
+	// Copy steps on every step to let paths be safely shared between siblings.
+	steps := make([]string, len(p.steps), len(p.steps)+1)
+	copy(steps, p.steps)
+
+	return valuePath{
+		root:  p.root,
+		steps: append(steps, s),
+	}
+}
+
+func (p valuePath) String() string {
+	return strings.Join(p.steps, "")
+}
+
+func formatKey(k reflect.Value) string {
+	for k.Kind() == reflect.Interface && !k.IsNil() {
+		k = k.Elem()
+	}
+
+	if k.Kind() == reflect.String {
+		return strconv.Quote(k.String())
+	}
+
+	return fmt.Sprint(k.Interface())
+}
+
+// pathPattern is a parsed path expression like Order.Items[*].CreatedAt.
+// Its steps are the same as in valuePath, except for the wildcards:
+//   - .* matches any field.
+//   - [*] matches any index or map key.
+type pathPattern []string
+
+// mustParsePattern parses a path expression and panics if it is malformed.
+// The expression can start with the root type name, with a dot or with a field name.
+func mustParsePattern(expr string) pathPattern {
+	res, err := parsePattern(expr)
+	if err != nil {
+		panic(fmt.Errorf("invalid path expression %q: %w", expr, err))
+	}
+
+	return res
+}
+
+func parsePattern(expr string) (pathPattern, error) {
+	var res pathPattern
+
+	rest := expr
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("missing field name at %d", len(expr)-len(rest))
+			}
+
+			res = append(res, rest[:end+1])
+			rest = rest[end+1:]
+		case '[':
+			end, err := indexEnd(rest)
+			if err != nil {
+				return nil, fmt.Errorf("%w at %d", err, len(expr)-len(rest))
+			}
+
+			res = append(res, rest[:end+1])
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected character %q at %d", rest[0], len(expr)-len(rest))
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	return res, nil
+}
+
+// indexEnd returns a position of ] closing index expression started at the beginning of s.
+func indexEnd(s string) (int, error) {
+	if len(s) > 1 && s[1] == '"' {
+		q, err := strconv.QuotedPrefix(s[1:])
+		if err != nil {
+			return 0, fmt.Errorf("malformed key: %w", err)
+		}
+
+		end := len(q) + 1
+		if end >= len(s) || s[end] != ']' {
+			return 0, fmt.Errorf("missing ]")
+		}
+
+		return end, nil
+	}
+
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return 0, fmt.Errorf("missing ]")
+	}
+	if end == 1 {
+		return 0, fmt.Errorf("empty index")
+	}
+
+	return end, nil
+}
+
+// match checks if the pattern matches the path.
+func (pp pathPattern) match(p valuePath) bool {
+	if len(pp) == len(p.steps)+1 && p.root != nil && pp[0] == "."+p.root.Name() {
+		pp = pp[1:]
+	}
+
+	if len(pp) != len(p.steps) {
+		return false
+	}
+
+	for i, s := range pp {
+		switch s {
+		case ".*":
+			if !strings.HasPrefix(p.steps[i], ".") {
+				return false
+			}
+		case "[*]":
+			if !strings.HasPrefix(p.steps[i], "[") {
+				return false
+			}
+		default:
+			if s != p.steps[i] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
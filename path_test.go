@@ -0,0 +1,99 @@
+package deepequal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathPattern(t *testing.T) {
+	type order struct{}
+
+	root := newPath(reflect.TypeOf(&order{}))
+	items := root.field("Items").index(3).field("CreatedAt")
+	tags := root.field("Tags").key(reflect.ValueOf("x"))
+
+	tests := []struct {
+		name    string
+		expr    string
+		path    valuePath
+		want    bool
+		invalid bool
+	}{
+		{
+			name: "root type name",
+			expr: "order.Items[*].CreatedAt",
+			path: items,
+			want: true,
+		},
+		{
+			name: "leading dot",
+			expr: ".Items[3].CreatedAt",
+			path: items,
+			want: true,
+		},
+		{
+			name: "field name first",
+			expr: "Items[*].*",
+			path: items,
+			want: true,
+		},
+		{
+			name: "index mismatch",
+			expr: "Items[2].CreatedAt",
+			path: items,
+			want: false,
+		},
+		{
+			name: "too short",
+			expr: "Items[*]",
+			path: items,
+			want: false,
+		},
+		{
+			name: "map key",
+			expr: `Tags["x"]`,
+			path: tags,
+			want: true,
+		},
+		{
+			name: "map key with brackets",
+			expr: `Tags["]"]`,
+			path: root.field("Tags").key(reflect.ValueOf("]")),
+			want: true,
+		},
+		{
+			name:    "missing bracket",
+			expr:    "Items[*.CreatedAt",
+			invalid: true,
+		},
+		{
+			name:    "empty field",
+			expr:    "Items..CreatedAt",
+			invalid: true,
+		},
+		{
+			name:    "empty index",
+			expr:    "Items[]",
+			invalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := parsePattern(tt.expr)
+			if tt.invalid {
+				if err == nil {
+					t.Errorf("error was expected for %q", tt.expr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := pattern.match(tt.path); got != tt.want {
+				t.Errorf("match %q against %s = %v, want %v", tt.expr, tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package deepequal
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/sirkon/deepequal/internal/diff"
@@ -12,6 +15,8 @@ import (
 
 const (
 	formatBold  = "\033[1m"
+	formatDim   = "\033[2m"
+	formatReset = "\033[0m"
 	formatGreen = "\033[32m"
 	formatRed   = "\033[31m"
 )
@@ -20,19 +25,30 @@ type printer struct {
 	buf         *bytes.Buffer
 	formatDepth int
 	isLeft      bool
+	opts        *options
+	color       bool
+
+	// Lines having highlighted content, these are marked with gutters in the plain text mode.
+	marked      map[int]bool
+	regionStart int
+	lineCount   int
+	lineCounted int
 }
 
-func newPrinter(isLeft bool) *printer {
+func newPrinter(isLeft bool, opts *options) *printer {
 	return &printer{
 		buf:         &bytes.Buffer{},
 		formatDepth: 0,
 		isLeft:      isLeft,
+		opts:        opts,
+		color:       opts.colored(),
 	}
 }
 
 func (p *printer) printValue(
 	offset string,
 	v reflect.Value,
+	path valuePath,
 	d diff.Diff,
 	isProto bool,
 	showType bool,
@@ -43,7 +59,6 @@ func (p *printer) printValue(
 	noff := offset + "  "
 
 	p.setColorOn()
-	defer p.setColorOff()
 
 	t := v.Type()
 	switch t.Kind() {
@@ -76,6 +91,11 @@ func (p *printer) printValue(
 		}
 
 	case reflect.String:
+		if td, ok := d.(*diff.Text); ok {
+			p.printText(offset, t, td)
+			return
+		}
+
 		if t == reflect.TypeOf("") {
 			_, _ = fmt.Fprintf(p.buf, "%q", v.Interface())
 		} else {
@@ -84,7 +104,7 @@ func (p *printer) printValue(
 
 	case reflect.Slice, reflect.Array:
 		if v.Len() == 0 {
-			if v.IsNil() {
+			if v.Kind() == reflect.Slice && v.IsNil() && !p.opts.equateEmpty {
 				_, _ = fmt.Fprintf(p.buf, "%s(nil)", v.Type().String())
 				return
 			}
@@ -93,17 +113,32 @@ func (p *printer) printValue(
 			return
 		}
 
+		if isBytes(t) {
+			p.printBytes(offset, v, d)
+			return
+		}
+
 		_, _ = fmt.Fprintf(p.buf, "%s{\n\r", v.Type().String())
 		ds := p.sliceDiff(d)
+		var folded []bool
+		if _, ok := d.(*diff.Indices); ok {
+			folded = p.folded(v.Len(), func(i int) bool { return ds[i] != nil })
+		}
 
 		for i := 0; i < v.Len(); i++ {
+			if n := foldRun(folded, i); n > 0 {
+				p.printFolded(noff, n, "elements")
+				i += n - 1
+				continue
+			}
+
 			vi := v.Index(i)
 			p.buf.WriteString(noff)
 
 			if vdiff := ds[i]; vdiff != nil {
-				p.printValue(noff, vi, vdiff, false, false, stack)
+				p.printValue(noff, vi, path.index(i), vdiff, false, false, stack)
 			} else {
-				p.printValue(noff, vi, nil, false, false, stack)
+				p.printValue(noff, vi, path.index(i), nil, false, false, stack)
 			}
 
 			p.buf.WriteString(",\n\r")
@@ -113,7 +148,7 @@ func (p *printer) printValue(
 
 	case reflect.Map:
 		if v.Len() == 0 {
-			if v.IsNil() {
+			if v.IsNil() && !p.opts.equateEmpty {
 				_, _ = fmt.Fprintf(p.buf, "%s(nil)", v.Type().String())
 				return
 			}
@@ -128,19 +163,30 @@ func (p *printer) printValue(
 		sort.Slice(keys, func(i, j int) bool {
 			return compareReflectValues(keys[i], keys[j])
 		})
+		var folded []bool
+		if _, ok := d.(*diff.Keys); ok {
+			folded = p.folded(len(keys), func(i int) bool { return ds.MapIndex(keys[i]).IsValid() })
+		}
 
-		for _, key := range keys {
+		for i := 0; i < len(keys); i++ {
+			if n := foldRun(folded, i); n > 0 {
+				p.printFolded(noff, n, "entries")
+				i += n - 1
+				continue
+			}
+
+			key := keys[i]
 			p.buf.WriteString(noff)
 
 			dm := ds.MapIndex(key)
 			if dm.IsValid() {
-				p.printValue(noff, key, dm.Interface().(diff.Diff), false, false, stack)
+				p.printValue(noff, key, path, dm.Interface().(diff.Diff), false, false, stack)
 				p.buf.WriteString(": ")
-				p.printValue(noff, v.MapIndex(key), dm.Interface().(diff.Diff), false, false, stack)
+				p.printValue(noff, v.MapIndex(key), path.key(key), dm.Interface().(diff.Diff), false, false, stack)
 			} else {
-				p.printValue(noff, key, nil, false, false, stack)
+				p.printValue(noff, key, path, nil, false, false, stack)
 				p.buf.WriteString(": ")
-				p.printValue(noff, v.MapIndex(key), nil, false, false, stack)
+				p.printValue(noff, v.MapIndex(key), path.key(key), nil, false, false, stack)
 			}
 
 			p.buf.WriteString(",\n\r")
@@ -158,38 +204,70 @@ func (p *printer) printValue(
 		_, _ = fmt.Fprintf(p.buf, "%s{\n\r", v.Type().String())
 		ds := p.structDiff(d)
 
+		// Proto service fields are not shown, so they are not entries to collapse.
+		fields := make([]int, 0, v.NumField())
 		for i := 0; i < v.NumField(); i++ {
 			if isProto && !t.Field(i).IsExported() {
 				continue
 			}
+			fields = append(fields, i)
+		}
+		var folded []bool
+		if ds != nil {
+			folded = p.folded(len(fields), func(i int) bool {
+				return structFieldDiff(ds, t.Field(fields[i]), getField(v, fields[i]), isProto) != nil
+			})
+		}
+
+		for j := 0; j < len(fields); j++ {
+			if n := foldRun(folded, j); n > 0 {
+				p.printFolded(noff, n, "fields")
+				j += n - 1
+				continue
+			}
 
+			i := fields[j]
 			fieldName := t.Field(i).Name
-			vs := ds[fieldName]
+			fpath := path.field(fieldName)
+			if name, ok := protoFieldName(t.Field(i)); ok && isProto {
+				fpath = path.field(name)
+			}
+			vs := structFieldDiff(ds, t.Field(i), getField(v, i), isProto)
 			p.buf.WriteString(noff)
 
+			if p.opts.ignoreField(t.Field(i), fpath) {
+				p.printIgnored(noff, fieldName, getField(v, i), fpath, stack)
+				continue
+			}
+
 			if vs != nil {
 				p.setFormatOn(vs)
 				p.setColorOn()
 				p.buf.WriteString(fieldName)
-				p.buf.WriteString("\033[0m: ")
-				p.printValue(noff, getField(v, i), vs, false, false, stack)
+				p.escape(formatReset)
+				p.buf.WriteString(": ")
+				p.printValue(noff, getField(v, i), fpath, vs, false, false, stack)
 				p.setFormatOff(vs)
-				p.setColorOff()
 			} else {
 				p.setColorOn()
 				p.buf.WriteString(fieldName)
-				p.setColorOff()
 				p.buf.WriteString(": ")
-				p.printValue(noff, getField(v, i), nil, false, false, stack)
+				p.printValue(noff, getField(v, i), fpath, nil, false, false, stack)
 			}
 
 			p.buf.WriteString(",\n\r")
 		}
 
+		if isProto {
+			for _, extra := range protoExtras(v) {
+				p.buf.WriteString(noff)
+				p.printExtra(noff, extra, path.field(extra.key), ds[extra.key], stack)
+			}
+		}
+
 		p.buf.WriteString(offset)
 		p.setColorOn()
 		p.buf.WriteString("}")
-		p.setColorOff()
 
 	case reflect.Pointer:
 		if v.IsNil() {
@@ -208,10 +286,17 @@ func (p *printer) printValue(
 		}
 		stack[addr] = struct{}{}
 
+		if p.opts.protoText {
+			if m, ok := getProtoMessage(v.Interface()); ok {
+				p.printProto(offset, m.ProtoReflect(), path, d)
+				return
+			}
+		}
+
 		p.buf.WriteByte('&')
 		// _, _ = fmt.Fprintf(p.buf, "(%x)", addr)
 		_, ip := v.Interface().(proto.Message)
-		p.printValue(offset, v.Elem(), d, ip, false, stack)
+		p.printValue(offset, v.Elem(), path, d, ip, false, stack)
 
 	case reflect.Interface:
 		if v.IsNil() {
@@ -221,31 +306,126 @@ func (p *printer) printValue(
 		}
 
 		var xxx proto.Message
-		p.printValue(offset, v.Elem(), d, t == reflect.TypeOf(xxx), true, stack)
+		p.printValue(offset, v.Elem(), path, d, t == reflect.TypeOf(xxx), true, stack)
+
+	case reflect.Func, reflect.Chan:
+		// Only nils can be equal, the address is all there is to show otherwise.
+		name := t.String()
+		if t.Name() == "" {
+			name = "(" + name + ")"
+		}
+		if v.IsNil() {
+			_, _ = fmt.Fprintf(p.buf, "%s(nil)", name)
+		} else {
+			_, _ = fmt.Fprintf(p.buf, "%s(%#x)", name, v.Pointer())
+		}
 
 	default:
 		panic(fmt.Errorf("type %s is not supported for printing", t.String()))
 	}
 }
 
-func (p *printer) setColorOn() {
-	if p.formatDepth == 0 {
+// printText prints a string with changed segments highlighted. Multiline strings
+// are printed as concatenations of their quoted lines, a line per output line.
+func (p *printer) printText(offset string, t reflect.Type, d *diff.Text) {
+	segments := d.Left
+	if !p.isLeft {
+		segments = d.Right
+	}
+
+	named := t != reflect.TypeOf("")
+	if named {
+		p.buf.WriteString(t.String())
+		p.buf.WriteByte('(')
+	}
+
+	if len(segments) == 0 {
+		p.buf.WriteString(`""`)
+	}
+
+	if d.Lines {
+		for i, segment := range segments {
+			if i > 0 {
+				p.buf.WriteString(" +\n\r")
+				p.buf.WriteString(offset)
+				p.buf.WriteString("  ")
+			}
+			p.printSegment(strconv.Quote(segment.Text), segment.Changed)
+		}
+	} else if len(segments) > 0 {
+		p.buf.WriteByte('"')
+		for _, segment := range segments {
+			quoted := strconv.Quote(segment.Text)
+			p.printSegment(quoted[1:len(quoted)-1], segment.Changed)
+		}
+		p.buf.WriteByte('"')
+	}
+
+	if !d.Lines && (segmentsChanged(d.Left) || segmentsChanged(d.Right)) {
+		// The string is changed even if this side has only characters removed from the other one.
+		p.markLines(p.line())
+	}
+
+	if named {
+		p.buf.WriteByte(')')
+	}
+}
+
+// segmentsChanged checks if there are changed segments.
+func segmentsChanged(segments []diff.Segment) bool {
+	for _, segment := range segments {
+		if segment.Changed {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *printer) printSegment(text string, changed bool) {
+	if !changed {
+		p.buf.WriteString(text)
 		return
 	}
 
-	color := formatRed
-	if p.isLeft {
-		color = formatGreen
+	p.highlightOn()
+	p.buf.WriteString(text)
+	p.highlightOff()
+}
+
+// printExtra prints an extension or unknown fields of a protobuf message.
+func (p *printer) printExtra(offset string, extra protoExtra, path valuePath, d diff.Diff, stack map[uintptr]struct{}) {
+	p.setFormatOn(d)
+	p.setColorOn()
+	p.buf.WriteString(extra.key)
+	p.buf.WriteString(": ")
+	p.printValue(offset, extra.value, path, d, false, false, stack)
+	p.buf.WriteString(",")
+	if extra.unknown {
+		p.buf.WriteString(" // unknown")
 	}
-	p.buf.WriteString(color)
+	p.setFormatOff(d)
+	p.buf.WriteString("\n\r")
+}
+
+// printIgnored prints a struct field excluded from comparison dimmed.
+func (p *printer) printIgnored(offset string, name string, v reflect.Value, path valuePath, stack map[uintptr]struct{}) {
+	p.escape(formatDim)
+	p.buf.WriteString(name)
+	p.buf.WriteString(": ")
+	p.printValue(offset, v, path, nil, false, false, stack)
+	p.buf.WriteString(", // ignored")
+	p.escape(formatReset)
+	p.setColorOn()
+	p.buf.WriteString("\n\r")
 }
 
-func (p *printer) setColorOff() {
+func (p *printer) setColorOn() {
 	if p.formatDepth == 0 {
 		return
 	}
 
-	p.buf.WriteString("\033[0n")
+	p.escape(p.highlight())
 }
 
 func (p *printer) setFormatOn(d diff.Diff) {
@@ -254,18 +434,11 @@ func (p *printer) setFormatOn(d diff.Diff) {
 	}
 
 	switch d.(type) {
-	case *diff.Indices, *diff.Fields, *diff.Keys:
+	case *diff.Indices, *diff.Fields, *diff.Keys, *diff.Text:
 		return
 	}
 
-	if p.formatDepth == 0 {
-		color := formatRed
-		if p.isLeft {
-			color = formatGreen
-		}
-		p.buf.WriteString(color)
-	}
-	p.formatDepth++
+	p.highlightOn()
 }
 
 func (p *printer) setFormatOff(d diff.Diff) {
@@ -274,16 +447,118 @@ func (p *printer) setFormatOff(d diff.Diff) {
 	}
 
 	switch d.(type) {
-	case *diff.Indices, *diff.Fields, *diff.Keys:
+	case *diff.Indices, *diff.Fields, *diff.Keys, *diff.Text:
 		return
 	}
 
+	p.highlightOff()
+}
+
+// highlightOn starts a highlighted region.
+func (p *printer) highlightOn() {
+	if p.formatDepth == 0 {
+		p.escape(p.highlight())
+		p.regionStart = p.line()
+	}
+	p.formatDepth++
+}
+
+// highlightOff ends a highlighted region.
+func (p *printer) highlightOff() {
 	p.formatDepth--
 	if p.formatDepth == 0 {
-		p.buf.WriteString("\033[0m")
+		p.escape(formatReset)
+		p.markLines(p.regionStart)
 	}
 }
 
+// highlight returns a color of differences for this side.
+func (p *printer) highlight() string {
+	if p.isLeft {
+		return formatGreen
+	}
+
+	return formatRed
+}
+
+// escape writes an ANSI escape sequence in the colored mode.
+func (p *printer) escape(seq string) {
+	if p.color {
+		p.buf.WriteString(seq)
+	}
+}
+
+// line returns the number of the current line.
+func (p *printer) line() int {
+	data := p.buf.Bytes()
+	p.lineCount += bytes.Count(data[p.lineCounted:], []byte{'\n'})
+	p.lineCounted = len(data)
+
+	return p.lineCount
+}
+
+// markLines marks lines from the given one up to the current one as having highlighted content.
+func (p *printer) markLines(from int) {
+	if p.marked == nil {
+		p.marked = map[int]bool{}
+	}
+
+	for l := from; l <= p.line(); l++ {
+		p.marked[l] = true
+	}
+}
+
+// column returns printed lines with a header. Differing lines get
+// -/+ gutters for left/right sides in the plain text mode.
+func (p *printer) column(header string) column {
+	var res column
+	if p.color {
+		res.lines = append(res.lines, formatBold+header+formatReset)
+	} else {
+		res.lines = append(res.lines, header)
+		res.gutters = append(res.gutters, p.gutter(false))
+	}
+
+	for i, line := range strings.Split(p.buf.String(), "\n") {
+		res.lines = append(res.lines, strings.Trim(line, "\r\n"))
+		if !p.color {
+			res.gutters = append(res.gutters, p.gutter(p.marked[i]))
+		}
+	}
+
+	return res
+}
+
+func (p *printer) gutter(marked bool) string {
+	switch {
+	case !marked:
+		return "  "
+	case p.isLeft:
+		return "- "
+	default:
+		return "+ "
+	}
+}
+
+// lineStart returns a prefix of the output. Test loggers put file:line
+// before it, a carriage return hides it in terminals.
+func (p *printer) lineStart() string {
+	if p.color {
+		return "\r"
+	}
+
+	return "\n"
+}
+
+// lineBreak returns a line separator of the output.
+func (p *printer) lineBreak() string {
+	if p.color {
+		return "\n\r"
+	}
+
+	return "\n"
+}
+
 func (p *printer) sliceDiff(v diff.Diff) map[int]diff.Diff {
 	vv, ok := v.(*diff.Indices)
 	if !ok {
@@ -319,49 +594,119 @@ func (p *printer) structDiff(v diff.Diff) map[string]diff.Diff {
 	return vv.Fields
 }
 
+// compareReflectValues is a total ordering of comparable values used to sort map keys.
 func compareReflectValues(a, b reflect.Value) bool {
-	if a.Type().Kind() != b.Type().Kind() {
-		return true
-	}
-
-	switch a.Type().Kind() {
-	case reflect.Int:
-		return getReflectValue[int](a) < getReflectValue[int](b)
-	case reflect.Int8:
-		return getReflectValue[int8](a) < getReflectValue[int8](b)
-	case reflect.Int16:
-		return getReflectValue[int16](a) < getReflectValue[int16](b)
-	case reflect.Int32:
-		return getReflectValue[int32](a) < getReflectValue[int32](b)
-	case reflect.Int64:
-		return getReflectValue[int64](a) < getReflectValue[int64](b)
-	case reflect.Uint:
-		return getReflectValue[uint](a) < getReflectValue[uint](b)
-	case reflect.Uint8:
-		return getReflectValue[uint8](a) < getReflectValue[uint8](b)
-	case reflect.Uint16:
-		return getReflectValue[uint16](a) < getReflectValue[uint16](b)
-	case reflect.Uint32:
-		return getReflectValue[uint32](a) < getReflectValue[uint32](b)
-	case reflect.Uint64:
-		return getReflectValue[uint64](a) < getReflectValue[uint64](b)
-	case reflect.Uintptr:
-		return getReflectValue[uintptr](a) < getReflectValue[uintptr](b)
-	case reflect.Float32:
-		return getReflectValue[float32](a) < getReflectValue[float32](b)
-	case reflect.Float64:
-		return getReflectValue[float64](a) < getReflectValue[float64](b)
+	return compareValues(a, b, 0) < 0
+}
+
+// maxCompareDepth limits pointers following, pointers are compared by addresses beyond it.
+const maxCompareDepth = 64
+
+// compareValues returns -1, 0 or 1 if a is less, equal or greater than b. Values of different
+// types are ordered by their kinds and then by their type names.
+func compareValues(a, b reflect.Value, depth int) int {
+	if !a.IsValid() || !b.IsValid() {
+		return compareBools(a.IsValid(), b.IsValid())
+	}
+
+	if a.Type() != b.Type() {
+		if a.Kind() != b.Kind() {
+			return compareOrdered(a.Kind(), b.Kind())
+		}
+		if c := strings.Compare(a.Type().String(), b.Type().String()); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Type().PkgPath(), b.Type().PkgPath())
+	}
+
+	switch a.Kind() {
+	case reflect.Bool:
+		return compareBools(a.Bool(), b.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareFloats(a.Float(), b.Float())
+	case reflect.Complex64, reflect.Complex128:
+		if c := compareFloats(real(a.Complex()), real(b.Complex())); c != 0 {
+			return c
+		}
+		return compareFloats(imag(a.Complex()), imag(b.Complex()))
 	case reflect.String:
-		return getReflectValue[string](a) < getReflectValue[string](b)
+		return strings.Compare(a.String(), b.String())
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if c := compareValues(a.Index(i), b.Index(i), depth); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if c := compareValues(a.Field(i), b.Field(i), depth); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return compareBools(!a.IsNil(), !b.IsNil())
+		}
+		return compareValues(a.Elem(), b.Elem(), depth)
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return compareBools(!a.IsNil(), !b.IsNil())
+		}
+		if a.Pointer() == b.Pointer() {
+			return 0
+		}
+		if depth < maxCompareDepth {
+			if c := compareValues(a.Elem(), b.Elem(), depth+1); c != 0 {
+				return c
+			}
+		}
+		return compareOrdered(a.Pointer(), b.Pointer())
 	default:
-		return true
+		// Channels and unsafe pointers can only be told apart by their addresses.
+		return compareOrdered(a.Pointer(), b.Pointer())
 	}
 }
 
-func getReflectValue[T any](v reflect.Value) T {
-	var tmp T
-	vv := reflect.ValueOf(&tmp)
-	vv.Elem().Set(v)
+func compareOrdered[T int64 | uint64 | uintptr | reflect.Kind](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
 
-	return vv.Elem().Interface().(T)
+// compareBools orders false before true.
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareFloats orders NaNs before all other values.
+func compareFloats(a, b float64) int {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b):
+		return compareBools(!math.IsNaN(a), !math.IsNaN(b))
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
 }
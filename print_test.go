@@ -3,6 +3,7 @@ package deepequal
 import (
 	"bytes"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
@@ -85,7 +86,7 @@ func TestPrint(t *testing.T) {
 			Fields: map[string]diff.Diff{
 				"b": &diff.Fields{
 					Fields: map[string]diff.Diff{
-						"Sub": &diff.Value{},
+						"sub": &diff.Value{},
 					},
 				},
 			},
@@ -127,7 +128,7 @@ func TestSunHighlight(t *testing.T) {
 			Fields: map[string]diff.Diff{
 				"b": &diff.Fields{
 					Fields: map[string]diff.Diff{
-						"Sub": &diff.Value{},
+						"sub": &diff.Value{},
 					},
 				},
 			},
@@ -135,12 +136,83 @@ func TestSunHighlight(t *testing.T) {
 	)
 }
 
+func TestPrintEquateEmpty(t *testing.T) {
+	p := newPrinter(false, newOptions([]Option{EquateEmpty()}))
+	p.printValue("", reflect.ValueOf([]int(nil)), valuePath{}, nil, false, false, map[uintptr]struct{}{})
+	if got := p.buf.String(); got != "[]int{}" {
+		t.Errorf("unexpected output %q", got)
+	}
+
+	p = newPrinter(false, newOptions([]Option{EquateEmpty()}))
+	p.printValue("", reflect.ValueOf(map[int]int(nil)), valuePath{}, nil, false, false, map[uintptr]struct{}{})
+	if got := p.buf.String(); got != "map[int]int{}" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
 func printItem(t *testing.T, v any, d diff.Diff) {
 	p := &printer{
 		buf:         &bytes.Buffer{},
 		formatDepth: 0,
 		isLeft:      false,
+		opts:        newOptions(nil),
 	}
-	p.printValue("", reflect.ValueOf(v), d, false, false, map[uintptr]struct{}{})
+	p.printValue("", reflect.ValueOf(v), valuePath{}, d, false, false, map[uintptr]struct{}{})
 	t.Log("\r", p.buf.String())
 }
+
+func TestCompareReflectValues(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	one, two := 1, 2
+
+	tests := []struct {
+		name string
+		keys []any
+	}{
+		{
+			name: "bools",
+			keys: []any{false, true},
+		},
+		{
+			name: "structs",
+			keys: []any{point{X: 1, Y: 2}, point{X: 1, Y: 3}, point{X: 2, Y: 0}},
+		},
+		{
+			name: "arrays",
+			keys: []any{[2]string{"a", "b"}, [2]string{"a", "c"}, [2]string{"b", "a"}},
+		},
+		{
+			name: "pointers",
+			keys: []any{(*int)(nil), &one, &two},
+		},
+		{
+			name: "mixed kinds",
+			keys: []any{nil, true, 1, 2, int64(0), "a", point{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := map[any]struct{}{}
+			for _, key := range tt.keys {
+				m[key] = struct{}{}
+			}
+
+			// Map iteration order is random, so repeat to catch an unstable ordering.
+			for i := 0; i < 20; i++ {
+				keys := reflect.ValueOf(m).MapKeys()
+				sort.Slice(keys, func(i, j int) bool {
+					return compareReflectValues(keys[i], keys[j])
+				})
+
+				for j, key := range keys {
+					if !reflect.DeepEqual(key.Interface(), tt.keys[j]) {
+						t.Fatalf("unexpected key %v at %d, want %v", key, j, tt.keys[j])
+					}
+				}
+			}
+		})
+	}
+}
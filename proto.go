@@ -0,0 +1,531 @@
+package deepequal
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirkon/deepequal/internal/diff"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// protoEqual compares messages the way proto.Equal does, but with options applied.
+func protoEqual(mx, my protoreflect.Message, opts *options, path valuePath) bool {
+	if mx.IsValid() != my.IsValid() {
+		return false
+	}
+
+	return protoMessageEqual(mx, my, opts, path)
+}
+
+func protoMessageEqual(mx, my protoreflect.Message, opts *options, path valuePath) bool {
+	if mx.Descriptor() != my.Descriptor() {
+		return false
+	}
+
+	if opts.proto.anyContent {
+		if ix, iy, ok := protoAnyContents(mx, my); ok {
+			return ix.Descriptor() == iy.Descriptor() && protoMessageEqual(ix, iy, opts, path)
+		}
+	}
+
+	equal := true
+	mx.Range(func(fd protoreflect.FieldDescriptor, vx protoreflect.Value) bool {
+		fpath := path.field(fd.TextName())
+		if opts.ignoreProtoField(fd, fpath) {
+			return true
+		}
+
+		if !my.Has(fd) {
+			equal = opts.proto.ignoreDefaults && protoDefault(fd, vx)
+			return equal
+		}
+
+		equal = protoFieldEqual(fd, vx, my.Get(fd), opts, fpath)
+		return equal
+	})
+	if !equal {
+		return false
+	}
+
+	my.Range(func(fd protoreflect.FieldDescriptor, vy protoreflect.Value) bool {
+		if opts.ignoreProtoField(fd, path.field(fd.TextName())) {
+			return true
+		}
+
+		equal = mx.Has(fd) || opts.proto.ignoreDefaults && protoDefault(fd, vy)
+		return equal
+	})
+	if !equal {
+		return false
+	}
+
+	return opts.proto.ignoreUnknown || protoUnknownEqual(mx.GetUnknown(), my.GetUnknown())
+}
+
+func protoFieldEqual(fd protoreflect.FieldDescriptor, x, y protoreflect.Value, opts *options, path valuePath) bool {
+	switch {
+	case fd.IsList():
+		return protoListEqual(fd, x.List(), y.List(), opts, path)
+	case fd.IsMap():
+		return protoMapEqual(fd, x.Map(), y.Map(), opts, path)
+	default:
+		return protoValueEqual(fd, x, y, opts, path)
+	}
+}
+
+func protoListEqual(fd protoreflect.FieldDescriptor, x, y protoreflect.List, opts *options, path valuePath) bool {
+	if x.Len() != y.Len() {
+		return false
+	}
+
+	if opts.protoUnordered(fd, x, path) {
+		left, _ := matchMultisets(x.Len(), y.Len(), func(i, j int) bool {
+			return protoValueEqual(fd, x.Get(i), y.Get(j), opts, path.index(i))
+		})
+		return len(left) == 0
+	}
+
+	for i := 0; i < x.Len(); i++ {
+		if !protoValueEqual(fd, x.Get(i), y.Get(i), opts, path.index(i)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func protoMapEqual(fd protoreflect.FieldDescriptor, x, y protoreflect.Map, opts *options, path valuePath) bool {
+	if x.Len() != y.Len() {
+		return false
+	}
+
+	equal := true
+	x.Range(func(k protoreflect.MapKey, vx protoreflect.Value) bool {
+		kpath := path.key(reflect.ValueOf(k.Interface()))
+		equal = y.Has(k) && protoValueEqual(fd.MapValue(), vx, y.Get(k), opts, kpath)
+		return equal
+	})
+
+	return equal
+}
+
+func protoValueEqual(fd protoreflect.FieldDescriptor, x, y protoreflect.Value, opts *options, path valuePath) bool {
+	switch fd.Kind() {
+	case protoreflect.FloatKind:
+		return opts.floats.equal(x.Float(), y.Float(), 32, true)
+	case protoreflect.DoubleKind:
+		return opts.floats.equal(x.Float(), y.Float(), 64, true)
+	case protoreflect.BytesKind:
+		return bytes.Equal(x.Bytes(), y.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoMessageEqual(x.Message(), y.Message(), opts, path)
+	default:
+		return x.Interface() == y.Interface()
+	}
+}
+
+// protoUnordered checks if the list field is to be compared regardless of elements order.
+func (o *options) protoUnordered(fd protoreflect.FieldDescriptor, list protoreflect.List, path valuePath) bool {
+	return matchProtoFields(o.proto.unordered, fd) || o.unordered(protoListElemType(fd, list), path)
+}
+
+// protoAnyContents unpacks contents of Any messages. Returns false if they are not Any
+// or if some of them cannot be unpacked.
+func protoAnyContents(mx, my protoreflect.Message) (protoreflect.Message, protoreflect.Message, bool) {
+	ix, _, ok := protoAnyContent(mx)
+	if !ok {
+		return nil, nil, false
+	}
+
+	iy, _, ok := protoAnyContent(my)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return ix, iy, true
+}
+
+// protoUnknownEqual compares unknown fields by their raw bytes grouped by field numbers.
+func protoUnknownEqual(x, y protoreflect.RawFields) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	if bytes.Equal(x, y) {
+		return true
+	}
+
+	return reflect.DeepEqual(groupUnknown(x), groupUnknown(y))
+}
+
+func groupUnknown(raw protoreflect.RawFields) map[protoreflect.FieldNumber]protoreflect.RawFields {
+	res := map[protoreflect.FieldNumber]protoreflect.RawFields{}
+	for len(raw) > 0 {
+		num, _, n := protowire.ConsumeField(raw)
+		if n < 0 {
+			// Malformed data, keep the rest as is to compare it raw.
+			res[-1] = raw
+			break
+		}
+
+		res[num] = append(res[num], raw[:n]...)
+		raw = raw[n:]
+	}
+
+	return res
+}
+
+// protoListElemType returns Go type of list elements.
+func protoListElemType(fd protoreflect.FieldDescriptor, list protoreflect.List) reflect.Type {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return reflect.TypeOf(list.NewElement().Message().Interface())
+	case protoreflect.EnumKind:
+		et, err := protoregistry.GlobalTypes.FindEnumByName(fd.Enum().FullName())
+		if err != nil {
+			return reflect.TypeOf(protoreflect.EnumNumber(0))
+		}
+
+		return reflect.TypeOf(et.New(0))
+	default:
+		return reflect.TypeOf(list.NewElement().Interface())
+	}
+}
+
+// protoFieldName returns a proto name of a field of the generated message struct.
+func protoFieldName(f reflect.StructField) (string, bool) {
+	for _, part := range strings.Split(f.Tag.Get("protobuf"), ",") {
+		if strings.HasPrefix(part, "name=") {
+			return part[len("name="):], true
+		}
+	}
+
+	return "", false
+}
+
+// protoDifference builds a difference of messages the way protoEqual compares them.
+// Fields are keyed by their proto names, oneofs having different cases set are keyed
+// by oneof names and unknown fields are keyed by their numbers.
+func protoDifference(mx, my protoreflect.Message, opts *options, path valuePath) diff.Diff {
+	if protoEqual(mx, my, opts, path) {
+		return nil
+	}
+
+	if mx.Descriptor() != my.Descriptor() {
+		return &diff.Type{
+			Left:  string(mx.Descriptor().FullName()),
+			Right: string(my.Descriptor().FullName()),
+		}
+	}
+
+	if opts.proto.anyContent {
+		if _, _, ok := protoAnyContents(mx, my); ok {
+			// Contents are rendered as Any fields, so they differ as a whole.
+			return &diff.Value{}
+		}
+	}
+
+	fields := map[protoreflect.FieldDescriptor]struct{}{}
+	collect := func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields[fd] = struct{}{}
+		return true
+	}
+	mx.Range(collect)
+	my.Range(collect)
+
+	res := &diff.Fields{
+		Fields: map[string]diff.Diff{},
+	}
+	for fd := range fields {
+		fpath := path.field(fd.TextName())
+		if opts.ignoreProtoField(fd, fpath) {
+			continue
+		}
+
+		if mx.Has(fd) != my.Has(fd) && opts.proto.ignoreDefaults && protoDefault(fd, mx.Get(fd)) && protoDefault(fd, my.Get(fd)) {
+			continue
+		}
+
+		if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+			if mx.WhichOneof(od) != my.WhichOneof(od) {
+				res.Fields[string(od.Name())] = &diff.Value{}
+				continue
+			}
+		}
+
+		if fd.HasPresence() && mx.Has(fd) != my.Has(fd) {
+			res.Fields[fd.TextName()] = &diff.Value{}
+			continue
+		}
+
+		if d := protoFieldDifference(fd, mx.Get(fd), my.Get(fd), opts, fpath); d != nil {
+			res.Fields[fd.TextName()] = d
+		}
+	}
+
+	if opts.proto.ignoreUnknown {
+		return res
+	}
+
+	ux := groupUnknown(mx.GetUnknown())
+	uy := groupUnknown(my.GetUnknown())
+	for num, raw := range ux {
+		if !bytes.Equal(raw, uy[num]) {
+			res.Fields[strconv.Itoa(int(num))] = &diff.Value{}
+		}
+	}
+	for num := range uy {
+		if _, ok := ux[num]; !ok {
+			res.Fields[strconv.Itoa(int(num))] = &diff.Value{}
+		}
+	}
+
+	return res
+}
+
+func protoFieldDifference(fd protoreflect.FieldDescriptor, x, y protoreflect.Value, opts *options, path valuePath) diff.Diff {
+	switch {
+	case fd.IsList():
+		return protoListDifference(fd, x.List(), y.List(), opts, path)
+	case fd.IsMap():
+		return protoMapDifference(fd, x.Map(), y.Map(), opts, path)
+	default:
+		return protoValueDifference(fd, x, y, opts, path)
+	}
+}
+
+func protoListDifference(fd protoreflect.FieldDescriptor, x, y protoreflect.List, opts *options, path valuePath) diff.Diff {
+	if protoListEqual(fd, x, y, opts, path) {
+		return nil
+	}
+	if x.Len() == 0 || y.Len() == 0 {
+		return &diff.Value{}
+	}
+
+	eq := func(i, j int) bool {
+		return protoValueEqual(fd, x.Get(i), y.Get(j), opts, path.index(i))
+	}
+	if opts.protoUnordered(fd, x, path) {
+		left, right := matchMultisets(x.Len(), y.Len(), eq)
+		res := &diff.Indices{
+			Left:  map[int]diff.Diff{},
+			Right: map[int]diff.Diff{},
+		}
+		for _, i := range left {
+			res.Left[i] = &diff.Missing{}
+		}
+		for _, j := range right {
+			res.Right[j] = &diff.Missing{}
+		}
+
+		return res
+	}
+
+	left, right := sequenceDiff(x.Len(), y.Len(), eq)
+	return &diff.Indices{
+		Left:  missingIndices(left),
+		Right: missingIndices(right),
+	}
+}
+
+func protoMapDifference(fd protoreflect.FieldDescriptor, x, y protoreflect.Map, opts *options, path valuePath) diff.Diff {
+	if protoMapEqual(fd, x, y, opts, path) {
+		return nil
+	}
+	if x.Len() == 0 || y.Len() == 0 {
+		return &diff.Value{}
+	}
+
+	res := &diff.Keys{
+		Left:  map[any]diff.Diff{},
+		Right: map[any]diff.Diff{},
+	}
+	x.Range(func(k protoreflect.MapKey, vx protoreflect.Value) bool {
+		if !y.Has(k) {
+			res.Left[k.Interface()] = &diff.Missing{}
+			return true
+		}
+
+		kpath := path.key(reflect.ValueOf(k.Interface()))
+		if d := protoValueDifference(fd.MapValue(), vx, y.Get(k), opts, kpath); d != nil {
+			res.Left[k.Interface()] = d
+			res.Right[k.Interface()] = d
+		}
+		return true
+	})
+	y.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		if !x.Has(k) {
+			res.Right[k.Interface()] = &diff.Missing{}
+		}
+		return true
+	})
+
+	return res
+}
+
+func protoValueDifference(fd protoreflect.FieldDescriptor, x, y protoreflect.Value, opts *options, path valuePath) diff.Diff {
+	if protoValueEqual(fd, x, y, opts, path) {
+		return nil
+	}
+
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoDifference(x.Message(), y.Message(), opts, path)
+	case protoreflect.StringKind:
+		return textDifference(x.String(), y.String())
+	case protoreflect.BytesKind:
+		if len(x.Bytes()) == 0 || len(y.Bytes()) == 0 {
+			return &diff.Value{}
+		}
+		return bytesDifference(x.Bytes(), y.Bytes())
+	default:
+		return &diff.Value{}
+	}
+}
+
+// protoStructFieldDiff returns a difference of a field of the generated message struct.
+// An active oneof case gets a difference of its wrapper struct.
+func protoStructFieldDiff(ds map[string]diff.Diff, f reflect.StructField, v reflect.Value) diff.Diff {
+	oneof, ok := f.Tag.Lookup("protobuf_oneof")
+	if !ok {
+		if name, ok := protoFieldName(f); ok {
+			return ds[name]
+		}
+
+		return ds[f.Name]
+	}
+
+	if d := ds[oneof]; d != nil {
+		return d
+	}
+	if v.IsNil() || v.Elem().IsNil() {
+		return nil
+	}
+
+	wf := v.Elem().Elem().Type().Field(0)
+	name, _ := protoFieldName(wf)
+	d := ds[name]
+	if d == nil {
+		return nil
+	}
+
+	return &diff.Fields{
+		Fields: map[string]diff.Diff{
+			wf.Name: d,
+		},
+	}
+}
+
+// structFieldDiff returns a difference of a struct field.
+func structFieldDiff(ds map[string]diff.Diff, f reflect.StructField, v reflect.Value, isProto bool) diff.Diff {
+	if isProto {
+		return protoStructFieldDiff(ds, f, v)
+	}
+
+	return ds[f.Name]
+}
+
+// protoExtra is a part of a message having no struct field: an extension or unknown fields with the same number.
+type protoExtra struct {
+	key     string
+	value   reflect.Value
+	unknown bool
+}
+
+// protoExtras returns extensions and unknown fields of the message struct sorted by their keys.
+func protoExtras(v reflect.Value) []protoExtra {
+	m, ok := protoMessageOf(v)
+	if !ok {
+		return nil
+	}
+
+	var res []protoExtra
+	m.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if xd, ok := fd.(protoreflect.ExtensionTypeDescriptor); ok {
+			res = append(res, protoExtra{
+				key:   fd.TextName(),
+				value: reflect.ValueOf(xd.Type().InterfaceOf(value)),
+			})
+		}
+		return true
+	})
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].key < res[j].key
+	})
+
+	unknown := groupUnknown(m.GetUnknown())
+	nums := make([]protoreflect.FieldNumber, 0, len(unknown))
+	for num := range unknown {
+		nums = append(nums, num)
+	}
+	sort.Slice(nums, func(i, j int) bool {
+		return nums[i] < nums[j]
+	})
+	for _, num := range nums {
+		res = append(res, protoExtra{
+			key:     strconv.Itoa(int(num)),
+			value:   reflect.ValueOf([]byte(unknown[num])),
+			unknown: true,
+		})
+	}
+
+	return res
+}
+
+// protoStructField returns a value of the message struct part with the given
+// key of a Fields difference. The result is invalid if there's no such part.
+func protoStructField(v reflect.Value, key string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		if oneof, ok := f.Tag.Lookup("protobuf_oneof"); ok {
+			fv := v.Field(i)
+			if oneof == key {
+				return fv
+			}
+			if fv.IsNil() || fv.Elem().IsNil() {
+				continue
+			}
+
+			wrapper := fv.Elem().Elem()
+			if name, _ := protoFieldName(wrapper.Type().Field(0)); name == key {
+				return wrapper.Field(0)
+			}
+			continue
+		}
+
+		if name, ok := protoFieldName(f); ok && name == key {
+			return v.Field(i)
+		}
+	}
+
+	for _, extra := range protoExtras(v) {
+		if extra.key == key {
+			return extra.value
+		}
+	}
+
+	return reflect.Value{}
+}
+
+// protoMessageOf returns a message of an addressable generated message struct.
+func protoMessageOf(v reflect.Value) (protoreflect.Message, bool) {
+	if v.Kind() != reflect.Struct || !v.CanAddr() {
+		return nil, false
+	}
+
+	m, ok := getProtoMessage(v.Addr().Interface())
+	if !ok {
+		return nil, false
+	}
+
+	return m.ProtoReflect(), true
+}
@@ -0,0 +1,147 @@
+package deepequal
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// IgnoreProtoFields excludes fields of protobuf messages from comparisons. Fields are set
+// either by their full names like pkg.Message.field or by message full names with
+// field numbers like pkg.Message.7. Extensions are set the same way. Panics on malformed names.
+func IgnoreProtoFields(names ...string) Option {
+	fields := make([]protoFieldRef, len(names))
+	for i, name := range names {
+		fields[i] = mustParseProtoField(name)
+	}
+
+	return func(o *options) {
+		o.proto.ignored = append(o.proto.ignored, fields...)
+	}
+}
+
+// IgnoreProtoUnknown excludes unknown fields of protobuf messages from comparisons.
+func IgnoreProtoUnknown() Option {
+	return func(o *options) {
+		o.proto.ignoreUnknown = true
+	}
+}
+
+// IgnoreProtoDefaults makes fields of protobuf messages set to default values, empty
+// messages included, equal to unset ones.
+func IgnoreProtoDefaults() Option {
+	return func(o *options) {
+		o.proto.ignoreDefaults = true
+	}
+}
+
+// IgnoreProtoOrder makes repeated fields of protobuf messages to be compared regardless
+// of elements order. Fields are set the same way as for IgnoreProtoFields.
+func IgnoreProtoOrder(names ...string) Option {
+	fields := make([]protoFieldRef, len(names))
+	for i, name := range names {
+		fields[i] = mustParseProtoField(name)
+	}
+
+	return func(o *options) {
+		o.proto.unordered = append(o.proto.unordered, fields...)
+	}
+}
+
+// EquateProtoAny makes google.protobuf.Any messages to be compared by their unpacked
+// contents instead of serialized bytes, which are not deterministic. Messages of
+// types missing in the global registry are compared as is.
+func EquateProtoAny() Option {
+	return func(o *options) {
+		o.proto.anyContent = true
+	}
+}
+
+type protoOptions struct {
+	ignored        []protoFieldRef
+	ignoreUnknown  bool
+	ignoreDefaults bool
+	unordered      []protoFieldRef
+	anyContent     bool
+}
+
+// custom checks if protobuf messages cannot be compared with proto.Equal.
+func (o protoOptions) custom() bool {
+	return len(o.ignored) > 0 || o.ignoreUnknown || o.ignoreDefaults || len(o.unordered) > 0 || o.anyContent
+}
+
+// protoFieldRef refers to a field of protobuf message either by its full name or by its number.
+type protoFieldRef struct {
+	name   protoreflect.FullName
+	number protoreflect.FieldNumber
+}
+
+func mustParseProtoField(name string) protoFieldRef {
+	pos := strings.LastIndexByte(name, '.')
+	if pos <= 0 || pos == len(name)-1 {
+		panic(fmt.Errorf("invalid protobuf field name %q: must look like pkg.Message.field or pkg.Message.1", name))
+	}
+
+	if num, err := strconv.ParseInt(name[pos+1:], 10, 32); err == nil {
+		return protoFieldRef{
+			name:   protoreflect.FullName(name[:pos]),
+			number: protoreflect.FieldNumber(num),
+		}
+	}
+
+	return protoFieldRef{
+		name: protoreflect.FullName(name),
+	}
+}
+
+func (r protoFieldRef) match(fd protoreflect.FieldDescriptor) bool {
+	if r.number == 0 {
+		return fd.FullName() == r.name
+	}
+
+	return fd.Number() == r.number && fd.ContainingMessage().FullName() == r.name
+}
+
+func matchProtoFields(refs []protoFieldRef, fd protoreflect.FieldDescriptor) bool {
+	for _, ref := range refs {
+		if ref.match(fd) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// protoDefault checks if the value of the field is the default one.
+func protoDefault(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+	switch {
+	case fd.IsList():
+		return v.List().Len() == 0
+	case fd.IsMap():
+		return v.Map().Len() == 0
+	}
+
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		m := v.Message()
+		if len(m.GetUnknown()) > 0 {
+			return false
+		}
+
+		empty := true
+		m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			empty = protoDefault(fd, v)
+			return empty
+		})
+		return empty
+	case protoreflect.BytesKind:
+		return bytes.Equal(v.Bytes(), fd.Default().Bytes())
+	case protoreflect.EnumKind:
+		return v.Enum() == fd.Default().Enum()
+	default:
+		return v.Interface() == fd.Default().Interface()
+	}
+}
@@ -0,0 +1,312 @@
+package deepequal
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirkon/deepequal/internal/diff"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// ProtoText makes SideBySideWith to render protobuf messages in the text format:
+// proto field names, enum names, timestamps and durations as strings, Any expanded.
+func ProtoText() Option {
+	return func(o *options) {
+		o.protoText = true
+	}
+}
+
+// printProto prints a message in the text format prefixed with its full name.
+func (p *printer) printProto(offset string, m protoreflect.Message, path valuePath, d diff.Diff) {
+	p.buf.WriteString(string(m.Descriptor().FullName()))
+	p.buf.WriteByte(' ')
+	p.printProtoMessage(offset, m, path, d)
+}
+
+// printProtoMessage prints message fields in braces.
+func (p *printer) printProtoMessage(offset string, m protoreflect.Message, path valuePath, d diff.Diff) {
+	// Well-known types are rendered differently from their fields, so they differ as a whole.
+	whole := d
+	if d != nil {
+		whole = &diff.Value{}
+	}
+
+	if text, ok := protoWellKnown(m); ok {
+		p.setFormatOn(whole)
+		p.buf.WriteString(text)
+		p.setFormatOff(whole)
+		return
+	}
+	if inner, url, ok := protoAnyContent(m); ok {
+		d = whole
+		p.setFormatOn(d)
+		p.buf.WriteString("{\n\r")
+		p.buf.WriteString(offset + "  [" + url + "]: ")
+		p.printProtoMessage(offset+"  ", inner, path, nil)
+		p.buf.WriteString("\n\r")
+		p.buf.WriteString(offset + "}")
+		p.setFormatOff(d)
+		return
+	}
+
+	noff := offset + "  "
+	ds := p.structDiff(d)
+	fields := protoPopulated(m)
+
+	var folded []bool
+	if ds != nil {
+		folded = p.folded(len(fields), func(i int) bool {
+			return protoFieldDiff(ds, fields[i]) != nil
+		})
+	}
+
+	p.buf.WriteString("{\n\r")
+	for i := 0; i < len(fields); i++ {
+		if n := foldRun(folded, i); n > 0 {
+			p.printFolded(noff, n, "fields")
+			i += n - 1
+			continue
+		}
+
+		fd := fields[i]
+		fpath := path.field(fd.TextName())
+		if p.opts.ignoreProtoField(fd, fpath) {
+			p.escape(formatDim)
+			p.printProtoField(noff, fd, m.Get(fd), fpath, nil)
+			p.buf.WriteString(" # ignored")
+			p.escape(formatReset)
+			p.setColorOn()
+			p.buf.WriteString("\n\r")
+			continue
+		}
+
+		p.printProtoField(noff, fd, m.Get(fd), fpath, protoFieldDiff(ds, fd))
+		p.buf.WriteString("\n\r")
+	}
+
+	unknown := groupUnknown(m.GetUnknown())
+	nums := make([]protoreflect.FieldNumber, 0, len(unknown))
+	for num := range unknown {
+		nums = append(nums, num)
+	}
+	sort.Slice(nums, func(i, j int) bool {
+		return nums[i] < nums[j]
+	})
+	for _, num := range nums {
+		ud := ds[strconv.Itoa(int(num))]
+		p.buf.WriteString(noff)
+		p.setFormatOn(ud)
+		_, _ = fmt.Fprintf(p.buf, "%d: %q # unknown", num, []byte(unknown[num]))
+		p.setFormatOff(ud)
+		p.buf.WriteString("\n\r")
+	}
+
+	p.buf.WriteString(offset)
+	p.setColorOn()
+	p.buf.WriteString("}")
+}
+
+// printProtoField prints a field, every element of a list or every entry of a map on its own line.
+func (p *printer) printProtoField(offset string, fd protoreflect.FieldDescriptor, v protoreflect.Value, path valuePath, d diff.Diff) {
+	name := fd.TextName()
+
+	switch {
+	case fd.IsList():
+		// A list differs as a whole if it is empty on the other side.
+		p.setFormatOn(d)
+		ds := p.sliceDiff(d)
+		list := v.List()
+		for i := 0; i < list.Len(); i++ {
+			if i > 0 {
+				p.buf.WriteString("\n\r")
+			}
+			p.buf.WriteString(offset)
+			p.setFormatOn(ds[i])
+			p.setColorOn()
+			p.buf.WriteString(name + ": ")
+			p.printProtoValue(offset, fd, list.Get(i), path.index(i), ds[i])
+			p.setFormatOff(ds[i])
+		}
+		p.setFormatOff(d)
+
+	case fd.IsMap():
+		p.setFormatOn(d)
+		ds := p.mapDiff(d)
+		entries := v.Map()
+		keys := make([]protoreflect.MapKey, 0, entries.Len())
+		entries.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		sort.Slice(keys, func(i, j int) bool {
+			return compareReflectValues(reflect.ValueOf(keys[i].Interface()), reflect.ValueOf(keys[j].Interface()))
+		})
+
+		for i, k := range keys {
+			if i > 0 {
+				p.buf.WriteString("\n\r")
+			}
+
+			kd := ds[k.Interface()]
+			kpath := path.key(reflect.ValueOf(k.Interface()))
+			p.buf.WriteString(offset)
+			p.setFormatOn(kd)
+			p.setColorOn()
+			p.buf.WriteString(name + ": {key: ")
+			p.printProtoValue(offset, fd.MapKey(), k.Value(), kpath, nil)
+			p.buf.WriteString(" value: ")
+			p.printProtoValue(offset, fd.MapValue(), entries.Get(k), kpath, kd)
+			p.buf.WriteString("}")
+			p.setFormatOff(kd)
+		}
+		p.setFormatOff(d)
+
+	default:
+		p.buf.WriteString(offset)
+		p.setFormatOn(d)
+		p.setColorOn()
+		p.buf.WriteString(name + ": ")
+		p.printProtoValue(offset, fd, v, path, d)
+		p.setFormatOff(d)
+	}
+}
+
+// printProtoValue prints a single value of the field.
+func (p *printer) printProtoValue(offset string, fd protoreflect.FieldDescriptor, v protoreflect.Value, path valuePath, d diff.Diff) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		p.printProtoMessage(offset, v.Message(), path, d)
+		return
+	case protoreflect.StringKind:
+		if td, ok := d.(*diff.Text); ok {
+			p.printText(offset, reflect.TypeOf(""), td)
+			return
+		}
+	case protoreflect.BytesKind:
+		if td, ok := d.(*diff.Text); ok {
+			p.printText(offset, reflect.TypeOf(""), td)
+			return
+		}
+		if _, ok := d.(*diff.Indices); ok {
+			d = &diff.Value{}
+		}
+	}
+
+	p.setFormatOn(d)
+	p.buf.WriteString(protoScalar(fd, v))
+	p.setFormatOff(d)
+}
+
+// protoScalar formats a scalar value the text format way.
+func protoScalar(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.StringKind:
+		return strconv.Quote(v.String())
+	case protoreflect.BytesKind:
+		return strconv.Quote(string(v.Bytes()))
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// protoPopulated returns populated fields in their declaration order followed by extensions.
+func protoPopulated(m protoreflect.Message) []protoreflect.FieldDescriptor {
+	var res []protoreflect.FieldDescriptor
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if m.Has(fields.Get(i)) {
+			res = append(res, fields.Get(i))
+		}
+	}
+
+	var exts []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if fd.IsExtension() {
+			exts = append(exts, fd)
+		}
+		return true
+	})
+	sort.Slice(exts, func(i, j int) bool {
+		return exts[i].Number() < exts[j].Number()
+	})
+
+	return append(res, exts...)
+}
+
+// protoFieldDiff returns a difference of the field. A field of oneof with different
+// cases set differs as a whole.
+func protoFieldDiff(ds map[string]diff.Diff, fd protoreflect.FieldDescriptor) diff.Diff {
+	if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+		if d := ds[string(od.Name())]; d != nil {
+			return d
+		}
+	}
+
+	return ds[fd.TextName()]
+}
+
+// protoWellKnown formats Timestamp and Duration messages as strings.
+func protoWellKnown(m protoreflect.Message) (string, bool) {
+	fields := m.Descriptor().Fields()
+	switch m.Descriptor().FullName() {
+	case "google.protobuf.Timestamp":
+		secs := m.Get(fields.ByNumber(1)).Int()
+		nanos := m.Get(fields.ByNumber(2)).Int()
+		return strconv.Quote(time.Unix(secs, nanos).UTC().Format(time.RFC3339Nano)), true
+	case "google.protobuf.Duration":
+		secs := m.Get(fields.ByNumber(1)).Int()
+		nanos := m.Get(fields.ByNumber(2)).Int()
+		return strconv.Quote(formatProtoDuration(secs, nanos)), true
+	default:
+		return "", false
+	}
+}
+
+// formatProtoDuration formats a duration like protojson does: seconds with an optional fraction.
+func formatProtoDuration(secs, nanos int64) string {
+	sign := ""
+	if secs < 0 || nanos < 0 {
+		sign = "-"
+		secs, nanos = -secs, -nanos
+	}
+
+	res := strconv.FormatInt(secs, 10)
+	if nanos != 0 {
+		frac := strings.TrimRight(fmt.Sprintf("%09d", nanos), "0")
+		res += "." + frac
+	}
+
+	return sign + res + "s"
+}
+
+// protoAnyContent unpacks Any message if its type is known.
+func protoAnyContent(m protoreflect.Message) (protoreflect.Message, string, bool) {
+	if m.Descriptor().FullName() != "google.protobuf.Any" {
+		return nil, "", false
+	}
+
+	fields := m.Descriptor().Fields()
+	url := m.Get(fields.ByNumber(1)).String()
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(url)
+	if err != nil {
+		return nil, "", false
+	}
+
+	inner := mt.New()
+	if err := proto.Unmarshal(m.Get(fields.ByNumber(2)).Bytes(), inner.Interface()); err != nil {
+		return nil, "", false
+	}
+
+	return inner, url, true
+}
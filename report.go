@@ -0,0 +1,227 @@
+package deepequal
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Report computes a flat list of differences between want and got.
+// It is empty if they are equal.
+func Report(want, got any, opts ...Option) Differences {
+	return reportValues(reflect.ValueOf(want), reflect.ValueOf(got), newOptions(opts))
+}
+
+// reportValues is Report for already reflected values.
+func reportValues(l, r reflect.Value, o *options) Differences {
+	node := diffValues(l, r, o)
+	if node == nil {
+		return nil
+	}
+
+	var root reflect.Type
+	if l.IsValid() {
+		root = l.Type()
+	}
+
+	var res Differences
+	res.collect(newPath(root), node, o)
+	return res
+}
+
+// Differences is a flat list of differences. Its String method gives one difference per line:
+//
+//	.Items[3].Price: want 10, got 12
+//	.Tags["x"]: missing on right
+type Differences []Difference
+
+func (ds Differences) String() string {
+	var buf strings.Builder
+	for i, d := range ds {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(d.String())
+	}
+
+	return buf.String()
+}
+
+// Difference is a single leaf of the difference tree.
+type Difference struct {
+	// Path to the different value, like .Items[3].Price or .Tags["x"]. Empty for the root.
+	Path string
+	// Node is the difference itself: *TypeNode, *ValueNode or *MissingNode.
+	Node Node
+}
+
+func (d Difference) String() string {
+	var buf strings.Builder
+	if d.Path != "" {
+		buf.WriteString(d.Path)
+		buf.WriteString(": ")
+	}
+
+	l, r := d.Node.Values()
+	switch d.Node.(type) {
+	case *TypeNode:
+		_, _ = fmt.Fprintf(&buf, "want %s, got %s", dynamicType(l), dynamicType(r))
+	case *MissingNode:
+		if l.IsValid() {
+			buf.WriteString("missing on right")
+		} else {
+			buf.WriteString("missing on left")
+		}
+	default:
+		_, _ = fmt.Fprintf(&buf, "want %s, got %s", formatShort(l), formatShort(r))
+	}
+
+	return buf.String()
+}
+
+func (ds *Differences) collect(path valuePath, node Node, opts *options) {
+	switch v := node.(type) {
+	case *FieldsNode:
+		names := make([]string, 0, len(v.Fields))
+		for name := range v.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		l, _ := v.Values()
+		for _, name := range names {
+			fpath := path.field(name)
+			if n, ok := v.Fields[name].(*IndicesNode); ok && opts.protoFieldUnordered(l, name) {
+				ds.collectIndices(fpath, n, true, opts)
+				continue
+			}
+
+			ds.collect(fpath, v.Fields[name], opts)
+		}
+
+	case *IndicesNode:
+		ds.collectIndices(path, v, false, opts)
+
+	case *KeysNode:
+		l, r := v.Values()
+		keys := mapKeys(deref(l), deref(r))
+		sort.Slice(keys, func(i, j int) bool {
+			return compareReflectValues(keys[i], keys[j])
+		})
+
+		for _, key := range keys {
+			n, ok := v.Left[key.Interface()]
+			if !ok {
+				n, ok = v.Right[key.Interface()]
+			}
+			if ok {
+				ds.collect(path.key(key), n, opts)
+			}
+		}
+
+	default:
+		*ds = append(*ds, Difference{
+			Path: path.String(),
+			Node: node,
+		})
+	}
+}
+
+// collectIndices collects differences of slice elements. Elements of slices compared regardless
+// of their order are never paired: the ones left unmatched are reported as missing.
+func (ds *Differences) collectIndices(path valuePath, v *IndicesNode, unordered bool, opts *options) {
+	l, r := v.Values()
+	ls, rs := deref(l), deref(r)
+	if unordered || opts.unordered(ls.Type().Elem(), path) {
+		for _, side := range []map[int]Node{v.Left, v.Right} {
+			indices := make([]int, 0, len(side))
+			for i := range side {
+				indices = append(indices, i)
+			}
+			sort.Ints(indices)
+
+			for _, i := range indices {
+				ds.collect(path.index(i), side[i], opts)
+			}
+		}
+		return
+	}
+
+	// Walk the edit script: runs of removed and added elements between equal ones.
+	var i, j int
+	for i < ls.Len() || j < rs.Len() {
+		di, dj := i, j
+		for di < ls.Len() && v.Left[di] != nil {
+			di++
+		}
+		for dj < rs.Len() && v.Right[dj] != nil {
+			dj++
+		}
+
+		switch {
+		case di == i && dj == j:
+			i++
+			j++
+			continue
+		case di-i == dj-j:
+			// Elements were changed rather than removed and added, look into them.
+			for k := 0; k < di-i; k++ {
+				lv, rv := ls.Index(i+k), rs.Index(j+k)
+				epath := path.index(i + k)
+				if n := exportDiff(lv, rv, difference(lv, rv, false, walkSet{}, opts, epath)); n != nil {
+					ds.collect(epath, n, opts)
+				}
+			}
+		default:
+			for k := i; k < di; k++ {
+				ds.collect(path.index(k), v.Left[k], opts)
+			}
+			for k := j; k < dj; k++ {
+				ds.collect(path.index(k), v.Right[k], opts)
+			}
+		}
+
+		i, j = di, dj
+	}
+}
+
+// protoFieldUnordered checks if the named field of the proto message is a repeated field
+// set by IgnoreProtoOrder.
+func (o *options) protoFieldUnordered(msg reflect.Value, name string) bool {
+	if !msg.IsValid() || !msg.Type().Implements(protoMessageType) {
+		return false
+	}
+	pb, ok := getProtoMessage(msg.Interface())
+	if !ok || pb == nil {
+		return false
+	}
+
+	fd := pb.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(name))
+	return fd != nil && fd.IsList() && matchProtoFields(o.proto.unordered, fd)
+}
+
+// formatShort formats a value to be shown in a single line.
+func formatShort(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+
+	if v.Kind() == reflect.String {
+		return strconv.Quote(v.String())
+	}
+
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// dynamicType returns a type of the value held in interfaces.
+func dynamicType(v reflect.Value) reflect.Type {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return v.Type()
+}
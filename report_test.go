@@ -0,0 +1,143 @@
+package deepequal_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sirkon/deepequal"
+	"github.com/sirkon/deepequal/internal/testdata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestReport(t *testing.T) {
+	type item struct {
+		Name  string
+		Price int
+	}
+	type order struct {
+		ID    string
+		Items []item
+		Tags  map[string]int
+		Note  any
+	}
+
+	want := order{
+		ID:    "1",
+		Items: []item{{Name: "a", Price: 10}, {Name: "b", Price: 2}},
+		Tags:  map[string]int{"x": 1, "y": 2},
+		Note:  "note",
+	}
+	got := order{
+		ID:    "2",
+		Items: []item{{Name: "a", Price: 10}, {Name: "c", Price: 3}},
+		Tags:  map[string]int{"y": 3},
+		Note:  1,
+	}
+
+	const expected = `.ID: want "1", got "2"
+.Items[1].Name: want "b", got "c"
+.Items[1].Price: want 2, got 3
+.Note: want string, got int
+.Tags["x"]: missing on right
+.Tags["y"]: want 2, got 3`
+	if report := deepequal.Report(want, got).String(); report != expected {
+		t.Errorf("unexpected report\n%s\nwant\n%s", report, expected)
+	}
+
+	// Runs of removed and added elements of different lengths cannot be paired.
+	got.Items = append(got.Items, item{Name: "d", Price: 4})
+	const expectedItems = `.Items[1]: missing on right
+.Items[1]: missing on left
+.Items[2]: missing on left`
+	if report := deepequal.Report(want, got, deepequal.IgnorePaths("ID", "Tags", "Note")).String(); report != expectedItems {
+		t.Errorf("unexpected report\n%s\nwant\n%s", report, expectedItems)
+	}
+
+	// Elements of unordered slices have no counterparts to be compared with.
+	const expectedUnordered = `[0]: missing on right
+[1]: missing on right
+[2]: missing on right
+[0]: missing on left
+[1]: missing on left
+[2]: missing on left`
+	if report := deepequal.Report([]int{1, 2, 3}, []int{4, 5, 6}, deepequal.IgnoreOrder()).String(); report != expectedUnordered {
+		t.Errorf("unexpected report of unordered slices\n%s\nwant\n%s", report, expectedUnordered)
+	}
+	if report := deepequal.Report([]int{1, 2, 3}, []int{3, 4, 1}, deepequal.IgnoreOrder()).String(); report != "[1]: missing on right\n[1]: missing on left" {
+		t.Errorf("unexpected report of unordered slices with common elements\n%s", report)
+	}
+
+	if report := deepequal.Report(want, want); len(report) != 0 {
+		t.Errorf("empty report expected, got\n%s", report)
+	}
+
+	if report := deepequal.Report(1, 2).String(); report != "want 1, got 2" {
+		t.Errorf("unexpected report %q", report)
+	}
+
+	if report := deepequal.Report(want, got, deepequal.IgnorePaths("ID", "Items", "Tags", "Note")); len(report) != 0 {
+		t.Errorf("empty report expected with ignored fields, got\n%s", report)
+	}
+
+	type pa struct{ A, B int }
+	type pb struct{ A int }
+	type holder struct{ V any }
+	if report := deepequal.Report(holder{pa{1, 2}}, holder{pb{1}}).String(); report != ".V: want deepequal_test.pa, got deepequal_test.pb" {
+		t.Errorf("unexpected report of different types of the same kind %q", report)
+	}
+
+	if report := deepequal.Report(map[any]int{nil: 1}, map[any]int{nil: 2}).String(); report != "[<nil>]: want 1, got 2" {
+		t.Errorf("unexpected report of a nil key %q", report)
+	}
+
+	type handlers struct {
+		Done chan struct{}
+		Call func()
+	}
+	report := deepequal.Report(handlers{Done: make(chan struct{})}, handlers{Call: func() {}})
+	if len(report) != 2 || report[0].Path != ".Call" || report[1].Path != ".Done" {
+		t.Errorf("unexpected report of functions and channels\n%s", report)
+	}
+}
+
+func TestReportProto(t *testing.T) {
+	want := &testdata.Envelope{
+		Id:       proto.String("1"),
+		Payload:  &testdata.Envelope_Item{Item: &testdata.Item{Name: "apple", Price: 10}},
+		Counters: map[string]int64{"a": 1, "b": 2},
+	}
+	got := &testdata.Envelope{
+		Id:       proto.String("1"),
+		Payload:  &testdata.Envelope_Item{Item: &testdata.Item{Name: "apple", Price: 12}},
+		Counters: map[string]int64{"a": 1, "b": 3},
+	}
+	proto.SetExtension(want, testdata.E_Trace, proto.String("t1"))
+	proto.SetExtension(got, testdata.E_Trace, proto.String("t2"))
+	got.ProtoReflect().SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 1000, protowire.VarintType), 5))
+
+	const expected = `.1000: want nil, got [192 62 5]
+.[sample.trace]: want "t1", got "t2"
+.counters["b"]: want 2, got 3
+.item.price: want 10, got 12`
+	if report := deepequal.Report(want, got).String(); report != expected {
+		t.Errorf("unexpected report\n%s\nwant\n%s", report, expected)
+	}
+
+	order := func(tags ...string) *testdata.Order {
+		return &testdata.Order{Id: "1", Tags: tags}
+	}
+	const expectedTags = `.tags[0]: missing on right
+.tags[0]: missing on left`
+	report := deepequal.Report(order("a", "x"), order("b", "x"), deepequal.IgnoreProtoOrder("sample.Order.tags")).String()
+	if report != expectedTags {
+		t.Errorf("unexpected report of unordered tags\n%s\nwant\n%s", report, expectedTags)
+	}
+
+	got.Payload = &testdata.Envelope_Text{Text: "apple"}
+	got.ProtoReflect().SetUnknown(nil)
+	proto.SetExtension(got, testdata.E_Trace, proto.String("t1"))
+	got.Counters["b"] = 2
+	if report := deepequal.Report(want, got); len(report) != 1 || report[0].Path != ".payload" {
+		t.Errorf("a difference of oneof cases expected, got\n%s", report)
+	}
+}
@@ -1,13 +1,10 @@
 package deepequal
 
 import (
-	"bytes"
 	"fmt"
 	"path/filepath"
 	"reflect"
 	"regexp"
-	"runtime"
-	"strings"
 )
 
 // TestPrinter basically a masque for testing.XXX.
@@ -15,118 +12,67 @@ type TestPrinter interface {
 	Helper()
 	Log(a ...any)
 	Error(a ...any)
+	Fatal(a ...any)
 }
 
 // SideBySide outputs a and b side by side with a difference highlight.
 func SideBySide[T any](p TestPrinter, what string, want, got T) {
+	p.Helper()
+	SideBySideWith(p, what, want, got)
+}
+
+// SideBySideWith does the same as SideBySide but with an ability to tune comparison
+// and output with options.
+func SideBySideWith[T any](p TestPrinter, what string, want, got T, opts ...Option) {
 	lv := reflect.ValueOf(want)
 	rv := reflect.ValueOf(got)
+	o := newOptions(opts)
 
 	// Look for *_test.go file in the call stack to show proper line.
 
 	p.Helper()
-	if !Equal(want, got) {
+	equal := equalValues(lv, rv, o, o.pathFor(lv))
+	if !equal {
 		p.Error("mismatched expected and actual values of", what)
 	} else {
 		p.Log(`a match for expected and actual values of`, what)
 	}
 
-	printDiff(p, lv, rv)
+	p.Log(renderDiff(lv, rv, o))
+
+	if !equal && o.actualLiteral {
+		p.Log(fmt.Sprintf("actual value of %s as Go literal:\n%s", what, newLiteralWriter().literal(rv)))
+	}
 }
 
 // linePrefix returns the first call position (<file>:<line>) made in some
 // *_test.go file. The result is either empty or is ended with the space.
 func linePrefix() string {
-	fpcs := make([]uintptr, 128)
-	frames := runtime.CallersFrames(fpcs)
-	n := runtime.Callers(0, fpcs)
-	if n == 0 {
+	frame, ok := testFrame()
+	if !ok {
 		return ""
 	}
-	fpcs = fpcs[:n]
-	frames = runtime.CallersFrames(fpcs)
-
-	for {
-		frame, more := frames.Next()
-		if strings.HasSuffix(frame.File, "_test.go") {
-			_, name := filepath.Split(frame.File)
-			return fmt.Sprintf("\r    %s:%d ", name, frame.Line)
-		}
-
-		if !more {
-			break
-		}
-	}
 
-	return ""
+	_, name := filepath.Split(frame.File)
+	return fmt.Sprintf("\r    %s:%d ", name, frame.Line)
 }
 
-func printDiff(p TestPrinter, l, r reflect.Value) {
-	diff := difference(l, r, false, walkSet{})
-
-	lp := newPrinter(true)
-	lp.printValue("", l, diff, false, true, map[uintptr]struct{}{})
-
-	rp := newPrinter(false)
-	rp.printValue("", r, diff, false, true, map[uintptr]struct{}{})
-
-	ldrs := strings.Split(lp.buf.String(), "\n")
-	rdrs := strings.Split(rp.buf.String(), "\n")
-	for i := range ldrs {
-		ldrs[i] = strings.Trim(ldrs[i], "\r\n")
-	}
-	for i := range rdrs {
-		rdrs[i] = strings.Trim(rdrs[i], "\r\n")
+// renderDiff renders the difference between l and r in the format set by options.
+func renderDiff(l, r reflect.Value, opts *options) string {
+	if opts.unified {
+		return renderUnified(l, r, opts)
 	}
 
-	lrs := append([]string{formatBold + "Expected\033[0m"}, ldrs...)
-	var strips []string
-	rrs := append([]string{formatBold + "Actual\033[0m"}, rdrs...)
-
-	max1 := 0
-	for _, lr := range lrs {
-		v := stripANSI(lr)
-		strips = append(strips, v)
-		ll := len(v)
-		if ll > max1 {
-			max1 = ll
-		}
-	}
-	max1++
+	diff := difference(l, r, false, walkSet{}, opts, opts.pathFor(l))
 
-	rs := len(lrs)
-	if len(rrs) > rs {
-		rs = len(rrs)
-	}
+	lp := newPrinter(true, opts)
+	lp.printValue("", l, opts.pathFor(l), diff, false, true, map[uintptr]struct{}{})
 
-	var res bytes.Buffer
-	for i := 0; i < rs; i++ {
-		var lc string
-		var rc string
-
-		if i < len(lrs) {
-			lc = lrs[i]
-		} else {
-			lc = strings.Repeat(" ", max1)
-		}
-		if i < len(rrs) {
-			rc = rrs[i]
-		}
-
-		lc = strings.Trim(lc, "\r\n")
-		if i < len(lrs) {
-			lc += strings.Repeat(" ", max1-len(strips[i]))
-		}
-
-		rc = strings.Trim(rc, "\r\n")
-
-		res.WriteString(lc)
-		res.WriteByte(' ')
-		res.WriteString(rc)
-		res.WriteString("\n\r")
-	}
+	rp := newPrinter(false, opts)
+	rp.printValue("", r, opts.pathFor(r), diff, false, true, map[uintptr]struct{}{})
 
-	p.Log("\r" + res.String())
+	res := layoutColumns(lp.column("Expected"), rp.column("Actual"), opts.width(), opts.elide, lp.lineBreak())
+	return lp.lineStart() + res
 }
 
 const ansi = "[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))"
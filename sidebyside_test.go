@@ -2,10 +2,17 @@ package deepequal_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/sirkon/deepequal"
 	"github.com/sirkon/deepequal/internal/testdata"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestSideBySide(t *testing.T) {
@@ -47,6 +54,83 @@ func TestSideBySide(t *testing.T) {
 	)
 }
 
+func TestSideBySideIgnoredFields(t *testing.T) {
+	type sample struct {
+		ID   string `deepequal:"-"`
+		Name string
+	}
+
+	deepequal.SideBySideWith(
+		quasiTesting{},
+		"ignored fields",
+		sample{ID: "1", Name: "a"},
+		sample{ID: "2", Name: "b"},
+	)
+}
+
+func TestSideBySidePlain(t *testing.T) {
+	type sample struct {
+		Name string
+		Tags []string
+	}
+
+	t.Setenv("COLUMNS", "200")
+
+	var rec recordingTesting
+	deepequal.SideBySideWith(
+		&rec,
+		"plain",
+		sample{Name: "a", Tags: []string{"x", "y"}},
+		sample{Name: "b", Tags: []string{"x", "z"}},
+		deepequal.Colors(deepequal.ColorNever),
+	)
+
+	const want = `
+  Expected                  Actual
+  deepequal_test.sample{    deepequal_test.sample{
+-   Name: "a",            +   Name: "b",
+    Tags: []string{           Tags: []string{
+      "x",                      "x",
+-     "y",                +     "z",
+    },                        },
+  }                         }
+`
+	if got := rec.logs.String(); got != want {
+		t.Errorf("unexpected output\n%s\nwant\n%s", got, want)
+	}
+	if !strings.Contains(rec.errors.String(), "mismatched") {
+		t.Errorf("mismatch must be reported")
+	}
+
+	rec = recordingTesting{}
+	deepequal.SideBySideWith(&rec, "colored", 1, 2, deepequal.Colors(deepequal.ColorAlways))
+	if got := rec.logs.String(); !strings.Contains(got, "\033[31m") || strings.Contains(got, "\033[0n") {
+		t.Errorf("unexpected colored output %q", got)
+	}
+}
+
+// recordingTesting records logs and errors.
+type recordingTesting struct {
+	logs   strings.Builder
+	errors strings.Builder
+	fatal  bool
+}
+
+func (r *recordingTesting) Helper() {}
+
+func (r *recordingTesting) Log(a ...any) {
+	r.logs.WriteString(fmt.Sprint(a...))
+}
+
+func (r *recordingTesting) Error(a ...any) {
+	r.errors.WriteString(fmt.Sprint(a...))
+}
+
+func (r *recordingTesting) Fatal(a ...any) {
+	r.errors.WriteString(fmt.Sprint(a...))
+	r.fatal = true
+}
+
 type quasiTesting struct{}
 
 func (q quasiTesting) Helper() {
@@ -60,3 +144,371 @@ func (q quasiTesting) Log(a ...any) {
 func (q quasiTesting) Error(a ...any) {
 	fmt.Print(a...)
 }
+
+func (q quasiTesting) Fatal(a ...any) {
+	fmt.Print(a...)
+}
+
+func TestSideBySideWidth(t *testing.T) {
+	type sample struct {
+		Name string
+	}
+
+	want := sample{Name: "a long long value to be wrapped"}
+	got := sample{Name: "short"}
+
+	var rec recordingTesting
+	deepequal.SideBySideWith(&rec, "wrapped", want, got, deepequal.Colors(deepequal.ColorNever), deepequal.Width(40))
+	const wrapped = `
+  Expected             Actual
+  deepequal_test.s↵    deepequal_test.s↵
+  ample{               ample{
+-   Name: "a long ↵  +   Name: "short",
+- long value to be↵  
+-  wrapped",         
+  }                    }
+`
+	if got := rec.logs.String(); got != wrapped {
+		t.Errorf("unexpected wrapped output\n%s\nwant\n%s", got, wrapped)
+	}
+
+	rec = recordingTesting{}
+	deepequal.SideBySideWith(
+		&rec,
+		"elided",
+		want,
+		got,
+		deepequal.Colors(deepequal.ColorNever),
+		deepequal.Width(40),
+		deepequal.ElideLongLines(),
+	)
+	const elided = `
+  Expected             Actual
+  deepequal_test.s…    deepequal_test.s…
+-   Name: "a long …  +   Name: "short",
+  }                    }
+`
+	if got := rec.logs.String(); got != elided {
+		t.Errorf("unexpected elided output\n%s\nwant\n%s", got, elided)
+	}
+
+	rec = recordingTesting{}
+	deepequal.SideBySideWith(&rec, "colored", want, got, deepequal.Colors(deepequal.ColorAlways), deepequal.Width(40))
+	for _, line := range strings.Split(rec.logs.String(), "\n") {
+		if i := strings.LastIndex(line, "\033["); i >= 0 && !strings.HasPrefix(line[i:], "\033[0m") {
+			t.Errorf("formatting must not leak out of a wrapped line %q", line)
+		}
+	}
+
+	// Too narrow widths are widened to fit at least a few characters per column.
+	for _, width := range []int{1, 2, 5, 18} {
+		rec = recordingTesting{}
+		deepequal.SideBySideWith(&rec, "narrow", want, got, deepequal.Colors(deepequal.ColorNever), deepequal.Width(width))
+		for _, line := range strings.Split(strings.Trim(rec.logs.String(), "\n"), "\n") {
+			if w := utf8.RuneCountInString(line); w > 18 {
+				t.Errorf("line %q of width %d is wider than the minimum 18 for width %d", line, w, width)
+			}
+		}
+	}
+	t.Setenv("COLUMNS", "5")
+	rec = recordingTesting{}
+	deepequal.SideBySideWith(&rec, "narrow terminal", want, got, deepequal.Colors(deepequal.ColorNever))
+	if !strings.Contains(rec.logs.String(), "-   Nam↵  +   Nam↵") {
+		t.Errorf("unexpected output in a narrow terminal\n%s", rec.logs.String())
+	}
+}
+
+func TestSideBySideLongString(t *testing.T) {
+	want := strings.Repeat("abcdefghij", 10000)
+	got := want[:50000] + "X" + want[50001:]
+
+	for _, mode := range []deepequal.ColorMode{deepequal.ColorNever, deepequal.ColorAlways} {
+		var rec recordingTesting
+		start := time.Now()
+		deepequal.SideBySideWith(&rec, "long string", want, got, deepequal.Width(80), deepequal.Colors(mode))
+		// Wrapping takes milliseconds, it took minutes when it was quadratic.
+		if elapsed := time.Since(start); elapsed > 10*time.Second {
+			t.Errorf("wrapping of a long string took %s", elapsed)
+		}
+
+		if mode != deepequal.ColorNever {
+			continue
+		}
+		for _, line := range strings.Split(rec.logs.String(), "\n") {
+			if w := utf8.RuneCountInString(line); w > 80 {
+				t.Fatalf("line %q is wider than 80", line)
+			}
+		}
+	}
+}
+
+func TestSideBySideFold(t *testing.T) {
+	type config struct {
+		A, B, C, D, E, F string
+		Items            []int
+		Limits           map[string]int
+	}
+
+	want := config{
+		A:      "a",
+		D:      "d",
+		Items:  []int{1, 2, 3, 4, 5, 6},
+		Limits: map[string]int{"a": 1, "b": 2, "c": 3, "d": 4},
+	}
+	got := want
+	got.A = "x"
+	got.Items = []int{1, 2, 3, 4, 5, 7}
+	got.Limits = map[string]int{"a": 1, "b": 2, "c": 3, "d": 5}
+
+	t.Setenv("COLUMNS", "200")
+
+	var rec recordingTesting
+	deepequal.SideBySideWith(&rec, "folded", want, got, deepequal.Colors(deepequal.ColorNever), deepequal.FoldUnchanged(1))
+
+	const output = `
+  Expected                            Actual
+  deepequal_test.config{              deepequal_test.config{
+-   A: "a",                         +   A: "x",
+    B: "",                              B: "",
+    ... 3 identical fields ...          ... 3 identical fields ...
+    F: "",                              F: "",
+    Items: []int{                       Items: []int{
+      ... 4 identical elements ...        ... 4 identical elements ...
+      5,                                  5,
+-     6,                            +     7,
+    },                                  },
+    Limits: map[string]int{             Limits: map[string]int{
+      ... 2 identical entries ...         ... 2 identical entries ...
+      "c": 3,                             "c": 3,
+-     "d": 4,                       +     "d": 5,
+    },                                  },
+  }                                   }
+`
+	if got := rec.logs.String(); got != output {
+		t.Errorf("unexpected output\n%s\nwant\n%s", got, output)
+	}
+}
+
+func TestSideBySideText(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+
+	type query struct {
+		Name string
+		SQL  string
+	}
+
+	var rec recordingTesting
+	deepequal.SideBySideWith(
+		&rec,
+		"text",
+		query{Name: "select users", SQL: "SELECT *\nFROM users\nWHERE id = 1\n"},
+		query{Name: "select user", SQL: "SELECT *\nFROM users\nWHERE id = 2\nLIMIT 1"},
+		deepequal.Colors(deepequal.ColorNever),
+	)
+
+	const output = `
+  Expected                   Actual
+  deepequal_test.query{      deepequal_test.query{
+-   Name: "select users",  +   Name: "select user",
+    SQL: "SELECT *\n" +        SQL: "SELECT *\n" +
+      "FROM users\n" +           "FROM users\n" +
+-     "WHERE id = 1\n",    +     "WHERE id = 2\n" +
+  }                        +     "LIMIT 1",
+                             }
+`
+	if got := rec.logs.String(); got != output {
+		t.Errorf("unexpected output\n%s\nwant\n%s", got, output)
+	}
+
+	rec = recordingTesting{}
+	deepequal.SideBySideWith(&rec, "colored", "hello world", "hello, world", deepequal.Colors(deepequal.ColorAlways))
+	if got := rec.logs.String(); !strings.Contains(got, "\"hello\033[31m,\033[0m world\"") {
+		t.Errorf("only changed runes must be highlighted, got %q", got)
+	}
+}
+
+func TestSideBySideBytes(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+
+	type packet struct {
+		Header [4]byte
+		Body   []byte
+		Text   []byte
+	}
+
+	var rec recordingTesting
+	deepequal.SideBySideWith(
+		&rec,
+		"bytes",
+		packet{Header: [4]byte{0xca, 0xfe, 0, 1}, Body: []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 0xff}, Text: []byte("hello world")},
+		packet{Header: [4]byte{0xca, 0xfe, 0, 2}, Body: []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 10, 0xff}, Text: []byte("hello, world")},
+		deepequal.Colors(deepequal.ColorNever),
+	)
+
+	const output = `
+  Expected                                             Actual
+  deepequal_test.packet{                               deepequal_test.packet{
+    Header: [4]uint8{                                    Header: [4]uint8{
+-     00000000  ca fe 00 01              |....|      +     00000000  ca fe 00 02              |....|
+    },                                                   },
+    Body: []uint8{                                       Body: []uint8{
+      00000000  00 01 02 03 04 05 06 07  |........|        00000000  00 01 02 03 04 05 06 07  |........|
+-     00000008  08 09 ff                 |...|       +     00000008  08 0a ff                 |...|
+    },                                                   },
+-   Text: []uint8("hello world"),                    +   Text: []uint8("hello, world"),
+  }                                                    }
+`
+	if got := rec.logs.String(); got != output {
+		t.Errorf("unexpected output\n%s\nwant\n%s", got, output)
+	}
+}
+
+func TestSideBySideProto(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+
+	want := &testdata.Envelope{
+		Id:       proto.String("1"),
+		Payload:  &testdata.Envelope_Item{Item: &testdata.Item{Name: "apple", Price: 10}},
+		Counters: map[string]int64{"a": 1, "b": 2},
+	}
+	got := &testdata.Envelope{
+		Id:       proto.String("1"),
+		Payload:  &testdata.Envelope_Item{Item: &testdata.Item{Name: "apple", Price: 12}},
+		Counters: map[string]int64{"a": 1, "b": 2},
+	}
+	proto.SetExtension(want, testdata.E_Trace, proto.String("t1"))
+	proto.SetExtension(got, testdata.E_Trace, proto.String("t2"))
+
+	var rec recordingTesting
+	deepequal.SideBySideWith(&rec, "proto", want, got, deepequal.Colors(deepequal.ColorNever))
+
+	const output = `
+  Expected                               Actual
+  &testdata.Envelope{                    &testdata.Envelope{
+    Id: &"1",                              Id: &"1",
+    Payload: &testdata.Envelope_Item{      Payload: &testdata.Envelope_Item{
+      Item: &testdata.Item{                  Item: &testdata.Item{
+        Name: "apple",                         Name: "apple",
+-       Price: 10,                     +       Price: 12,
+        Weight: 0,                             Weight: 0,
+        Discount: 0,                           Discount: 0,
+      },                                     },
+    },                                     },
+    Counters: map[string]int64{            Counters: map[string]int64{
+      "a": 1,                                "a": 1,
+      "b": 2,                                "b": 2,
+    },                                     },
+-   [sample.trace]: "t1",              +   [sample.trace]: "t2",
+  }                                      }
+`
+	if got := rec.logs.String(); got != output {
+		t.Errorf("unexpected output\n%s\nwant\n%s", got, output)
+	}
+}
+
+func TestSideBySideProtoText(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+
+	want := &testdata.Envelope{
+		Id:       proto.String("1"),
+		Payload:  &testdata.Envelope_Item{Item: &testdata.Item{Name: "apple", Price: 10}},
+		Counters: map[string]int64{"a": 1, "b": 2},
+	}
+	got := &testdata.Envelope{
+		Id:       proto.String("1"),
+		Payload:  &testdata.Envelope_Item{Item: &testdata.Item{Name: "apple", Price: 12}},
+		Counters: map[string]int64{"a": 1, "b": 3},
+	}
+	proto.SetExtension(want, testdata.E_Trace, proto.String("t1"))
+	proto.SetExtension(got, testdata.E_Trace, proto.String("t2"))
+
+	var rec recordingTesting
+	deepequal.SideBySideWith(&rec, "proto", want, got, deepequal.Colors(deepequal.ColorNever), deepequal.ProtoText())
+
+	const output = `
+  Expected                           Actual
+  sample.Envelope {                  sample.Envelope {
+    id: "1"                            id: "1"
+    item: {                            item: {
+      name: "apple"                      name: "apple"
+-     price: 10                    +     price: 12
+    }                                  }
+    counters: {key: "a" value: 1}      counters: {key: "a" value: 1}
+-   counters: {key: "b" value: 2}  +   counters: {key: "b" value: 3}
+-   [sample.trace]: "t1"           +   [sample.trace]: "t2"
+  }                                  }
+`
+	if got := rec.logs.String(); got != output {
+		t.Errorf("unexpected output\n%s\nwant\n%s", got, output)
+	}
+
+	type event struct {
+		At      *timestamppb.Timestamp
+		Timeout *durationpb.Duration
+		Payload *anypb.Any
+	}
+	payload, err := anypb.New(&testdata.Item{Name: "apple"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec = recordingTesting{}
+	deepequal.SideBySideWith(
+		&rec,
+		"well known",
+		event{At: timestamppb.New(time.Unix(0, 0)), Timeout: durationpb.New(1500 * time.Millisecond), Payload: payload},
+		event{At: timestamppb.New(time.Unix(10, 0)), Timeout: durationpb.New(1500 * time.Millisecond), Payload: payload},
+		deepequal.Colors(deepequal.ColorNever),
+		deepequal.ProtoText(),
+	)
+
+	const wellKnown = `
+  Expected                                                   Actual
+  deepequal_test.event{                                      deepequal_test.event{
+-   At: google.protobuf.Timestamp "1970-01-01T00:00:00Z",  +   At: google.protobuf.Timestamp "1970-01-01T00:00:10Z",
+    Timeout: google.protobuf.Duration "1.5s",                  Timeout: google.protobuf.Duration "1.5s",
+    Payload: google.protobuf.Any {                             Payload: google.protobuf.Any {
+      [type.googleapis.com/sample.Item]: {                       [type.googleapis.com/sample.Item]: {
+        name: "apple"                                              name: "apple"
+      }                                                          }
+    },                                                         },
+  }                                                          }
+`
+	if got := rec.logs.String(); got != wellKnown {
+		t.Errorf("unexpected output\n%s\nwant\n%s", got, wellKnown)
+	}
+}
+
+func TestSideBySideActualLiteral(t *testing.T) {
+	type sample struct {
+		Name string
+		Tags []string
+	}
+
+	var rec recordingTesting
+	deepequal.SideBySideWith(
+		&rec,
+		"literal",
+		sample{Name: "a"},
+		sample{Name: "b", Tags: []string{"x"}},
+		deepequal.Colors(deepequal.ColorNever),
+		deepequal.ActualLiteral(),
+	)
+
+	const literal = `actual value of literal as Go literal:
+sample{
+	Name: "b",
+	Tags: []string{
+		"x",
+	},
+}`
+	if got := rec.logs.String(); !strings.Contains(got, literal) {
+		t.Errorf("actual value literal expected, got\n%s", got)
+	}
+
+	rec = recordingTesting{}
+	deepequal.SideBySideWith(&rec, "literal", sample{Name: "a"}, sample{Name: "a"}, deepequal.ActualLiteral())
+	if got := rec.logs.String(); strings.Contains(got, "Go literal") {
+		t.Errorf("no literal expected for matching values, got\n%s", got)
+	}
+}
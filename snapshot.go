@@ -0,0 +1,132 @@
+package deepequal
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+// SnapshotUpdateEnv is an environment variable to set for Snapshot to rewrite stored snapshots.
+const SnapshotUpdateEnv = "DEEPEQUAL_UPDATE"
+
+// Snapshot compares got with the snapshot stored in testdata/<name>.golden and reports
+// a mismatch with the side-by-side output. Protobuf messages are stored in the text
+// format and other values in JSON, both sides are compared with EqualWith after
+// decoding. Values that cannot be decoded back as they are, like ones with unexported
+// fields, are stored as Go literals and compared as texts.
+//
+// Snapshots are rewritten instead when tests are run with the -update flag, if the
+// test package defines it, or with DEEPEQUAL_UPDATE environment variable set.
+func Snapshot[T any](p TestPrinter, name string, got T, opts ...Option) {
+	p.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	data, actual, err := encodeSnapshot(got, opts)
+	if err != nil {
+		// Cannot be decoded back as is, compare as texts then.
+		data = []byte(newLiteralWriter().literal(reflect.ValueOf(got)) + "\n")
+	}
+
+	if snapshotUpdate() {
+		if err := writeSnapshot(path, data); err != nil {
+			p.Error(fmt.Sprintf("update snapshot %s: %s", name, err))
+			return
+		}
+
+		p.Log(fmt.Sprintf("snapshot %s updated", name))
+		return
+	}
+
+	stored, rerr := os.ReadFile(path)
+	if rerr != nil {
+		p.Error(fmt.Sprintf("read snapshot %s: %s, run tests with %s=1 to create it", name, rerr, SnapshotUpdateEnv))
+		return
+	}
+
+	if err != nil {
+		if string(stored) != string(data) {
+			SideBySideWith(p, "snapshot "+name, string(stored), string(data), opts...)
+		}
+		return
+	}
+
+	var want T
+	if err := decodeSnapshot(stored, &want); err != nil {
+		p.Error(fmt.Sprintf("decode snapshot %s: %s", name, err))
+		return
+	}
+
+	if !EqualWith(want, actual, opts...) {
+		SideBySideWith(p, "snapshot "+name, want, actual, opts...)
+	}
+}
+
+// encodeSnapshot serializes the value and decodes it back to get what will be stored.
+// Fails if the decoded value differs, like with unexported fields dropped by JSON.
+func encodeSnapshot[T any](v T, opts []Option) ([]byte, T, error) {
+	var res T
+
+	var data []byte
+	var err error
+	if m, ok := any(v).(proto.Message); ok {
+		data, err = prototext.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(m)
+	} else {
+		data, err = json.MarshalIndent(v, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return nil, res, err
+	}
+
+	if err := decodeSnapshot(data, &res); err != nil {
+		return nil, res, err
+	}
+	if !EqualWith(v, res, opts...) {
+		return nil, res, errors.New("the value does not survive serialization")
+	}
+
+	return data, res, nil
+}
+
+func decodeSnapshot[T any](data []byte, dst *T) error {
+	t := typeOf[T]()
+	if t.Kind() == reflect.Pointer && t.Implements(protoMessageType) {
+		v := reflect.New(t.Elem())
+		if err := prototext.Unmarshal(data, v.Interface().(proto.Message)); err != nil {
+			return err
+		}
+
+		reflect.ValueOf(dst).Elem().Set(v)
+		return nil
+	}
+
+	return json.Unmarshal(data, dst)
+}
+
+func writeSnapshot(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// snapshotUpdate checks if snapshots are to be rewritten.
+func snapshotUpdate() bool {
+	if f := flag.Lookup("update"); f != nil {
+		if update, err := strconv.ParseBool(f.Value.String()); err == nil && update {
+			return true
+		}
+	}
+
+	update, _ := strconv.ParseBool(os.Getenv(SnapshotUpdateEnv))
+	return update
+}
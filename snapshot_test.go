@@ -0,0 +1,110 @@
+package deepequal_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirkon/deepequal"
+	"github.com/sirkon/deepequal/internal/testdata"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+	inTempDir(t)
+
+	type sample struct {
+		Name  string
+		Tags  []string
+		Count map[string]int
+	}
+	got := sample{
+		Name:  "sample",
+		Tags:  []string{"a", "b"},
+		Count: map[string]int{"a": 1},
+	}
+
+	type secret struct {
+		token string
+		Key   string `json:"-"`
+	}
+	hidden := secret{token: "t", Key: "k"}
+
+	var rec recordingTesting
+	deepequal.Snapshot(&rec, "missing", got)
+	if !strings.Contains(rec.errors.String(), "read snapshot missing") {
+		t.Errorf("a missing snapshot must be reported, got %q", rec.errors.String())
+	}
+
+	t.Setenv(deepequal.SnapshotUpdateEnv, "1")
+	rec = recordingTesting{}
+	deepequal.Snapshot(&rec, "sample", got)
+	deepequal.Snapshot(&rec, "proto", &testdata.Item{Name: "item", Price: 12})
+	deepequal.Snapshot(&rec, "chan", map[string]chan int{"c": nil})
+	deepequal.Snapshot(&rec, "unexported", hidden)
+	if rec.errors.Len() != 0 {
+		t.Fatalf("unexpected errors on update: %s", rec.errors.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join("testdata", "sample.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "Name": "sample",
+  "Tags": [
+    "a",
+    "b"
+  ],
+  "Count": {
+    "a": 1
+  }
+}
+`
+	if string(data) != expected {
+		t.Errorf("unexpected JSON snapshot %q", data)
+	}
+
+	t.Setenv(deepequal.SnapshotUpdateEnv, "")
+	rec = recordingTesting{}
+	deepequal.Snapshot(&rec, "sample", got)
+	deepequal.Snapshot(&rec, "proto", &testdata.Item{Name: "item", Price: 12})
+	deepequal.Snapshot(&rec, "chan", map[string]chan int{"c": nil})
+	deepequal.Snapshot(&rec, "unexported", hidden)
+	if rec.errors.Len() != 0 {
+		t.Errorf("unexpected mismatches: %s", rec.errors.String())
+	}
+
+	got.Tags = append(got.Tags, "c")
+	deepequal.Snapshot(&rec, "sample", got, deepequal.Colors(deepequal.ColorNever))
+	deepequal.Snapshot(&rec, "proto", &testdata.Item{Name: "item", Price: 13}, deepequal.Colors(deepequal.ColorNever))
+	deepequal.Snapshot(&rec, "chan", map[string]chan int{"d": nil}, deepequal.Colors(deepequal.ColorNever))
+	deepequal.Snapshot(&rec, "unexported", secret{token: "t"}, deepequal.Colors(deepequal.ColorNever))
+	for _, what := range []string{"snapshot sample", "snapshot proto", "snapshot chan", "snapshot unexported"} {
+		if !strings.Contains(rec.errors.String(), what) {
+			t.Errorf("mismatch of %s is not reported: %s", what, rec.errors.String())
+		}
+	}
+	for _, line := range []string{`"c",`, "Price: 13,", `\"d\": nil,`} {
+		if !strings.Contains(rec.logs.String(), line) {
+			t.Errorf("line %q is missing in the output:\n%s", line, rec.logs.String())
+		}
+	}
+}
+
+// inTempDir runs the rest of the test in a temporary directory.
+func inTempDir(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package deepequal
+
+import (
+	"os"
+)
+
+// terminalWidth returns a width of the terminal the file is attached to. Always 0 on this platform.
+func terminalWidth(f *os.File) int {
+	return 0
+}
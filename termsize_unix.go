@@ -0,0 +1,24 @@
+//go:build linux || darwin
+
+package deepequal
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// terminalWidth returns a width of the terminal the file is attached to. Returns 0 if it is not a terminal.
+func terminalWidth(f *os.File) int {
+	var ws struct {
+		row, col       uint16
+		xpixel, ypixel uint16
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	}
+
+	return int(ws.col)
+}
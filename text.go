@@ -0,0 +1,96 @@
+package deepequal
+
+import (
+	"strings"
+
+	"github.com/sirkon/deepequal/internal/diff"
+)
+
+// textDifference builds an intra-string difference. Multiline strings are diffed
+// by lines and others by runes. Strings having too little in common to make
+// the rune difference readable differ as a whole.
+func textDifference(l, r string) diff.Diff {
+	if strings.Contains(l, "\n") || strings.Contains(r, "\n") {
+		ls := splitLines(l)
+		rs := splitLines(r)
+		left, right := sequenceDiff(len(ls), len(rs), func(i, j int) bool {
+			return ls[i] == rs[j]
+		})
+
+		return &diff.Text{
+			Lines: true,
+			Left:  lineSegments(ls, left),
+			Right: lineSegments(rs, right),
+		}
+	}
+
+	ls := []rune(l)
+	rs := []rune(r)
+	left, right := sequenceDiff(len(ls), len(rs), func(i, j int) bool {
+		return ls[i] == rs[j]
+	})
+
+	var common int
+	for _, changed := range left {
+		if !changed {
+			common++
+		}
+	}
+	longest := len(ls)
+	if len(rs) > longest {
+		longest = len(rs)
+	}
+	if 2*common < longest {
+		return &diff.Value{}
+	}
+
+	return &diff.Text{
+		Left:  runeSegments(ls, left),
+		Right: runeSegments(rs, right),
+	}
+}
+
+// splitLines splits a string into lines keeping line breaks.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	res := strings.SplitAfter(s, "\n")
+	if res[len(res)-1] == "" {
+		res = res[:len(res)-1]
+	}
+
+	return res
+}
+
+func lineSegments(lines []string, changed []bool) []diff.Segment {
+	res := make([]diff.Segment, len(lines))
+	for i, line := range lines {
+		res[i] = diff.Segment{
+			Text:    line,
+			Changed: changed[i],
+		}
+	}
+
+	return res
+}
+
+// runeSegments joins runs of runes having the same change status into segments.
+func runeSegments(rs []rune, changed []bool) []diff.Segment {
+	var res []diff.Segment
+	for i := 0; i < len(rs); {
+		j := i
+		for j < len(rs) && changed[j] == changed[i] {
+			j++
+		}
+
+		res = append(res, diff.Segment{
+			Text:    string(rs[i:j]),
+			Changed: changed[i],
+		})
+		i = j
+	}
+
+	return res
+}
@@ -0,0 +1,198 @@
+package deepequal
+
+import (
+	"reflect"
+
+	"github.com/sirkon/deepequal/internal/diff"
+)
+
+// Diff computes a difference tree between want and got. Returns nil if they are equal.
+func Diff(want, got any, opts ...Option) Node {
+	return diffValues(reflect.ValueOf(want), reflect.ValueOf(got), newOptions(opts))
+}
+
+// diffValues is Diff for already reflected values.
+func diffValues(l, r reflect.Value, o *options) Node {
+	if equalValues(l, r, o, o.pathFor(l)) {
+		return nil
+	}
+
+	if !l.IsValid() || !r.IsValid() {
+		return &ValueNode{values: values{left: l, right: r}}
+	}
+
+	return exportDiff(l, r, difference(l, r, false, walkSet{}, o, o.pathFor(l)))
+}
+
+// Node is a node of a difference tree. It is one of
+//
+//   - *TypeNode: values have different types.
+//   - *ValueNode: values differ as a whole.
+//   - *MissingNode: a slice element or a map entry exists on one side only.
+//   - *FieldsNode: struct fields differ.
+//   - *IndicesNode: slice or array elements differ.
+//   - *KeysNode: map entries differ.
+type Node interface {
+	// Values returns left (expected) and right (actual) values of the node.
+	// One of them is invalid for *MissingNode.
+	Values() (left, right reflect.Value)
+
+	isNode()
+}
+
+// TypeNode values have different types.
+type TypeNode struct {
+	values
+}
+
+// ValueNode values differ as a whole.
+type ValueNode struct {
+	values
+}
+
+// MissingNode represents a slice element or a map entry which exists on one side only.
+type MissingNode struct {
+	values
+}
+
+// FieldsNode represents structs having some different fields.
+type FieldsNode struct {
+	values
+
+	// Fields maps names of different fields into their differences. Fields of protobuf
+	// messages are named by their proto names, extensions by their full names in brackets,
+	// unknown fields by their numbers and oneofs having different cases set by oneof names.
+	Fields map[string]Node
+}
+
+// IndicesNode represents slices or arrays having some different elements.
+type IndicesNode struct {
+	values
+
+	// Left maps indices of left elements into their differences.
+	Left map[int]Node
+	// Right maps indices of right elements into their differences.
+	Right map[int]Node
+}
+
+// KeysNode represents maps having some different entries.
+type KeysNode struct {
+	values
+
+	// Left maps keys of left entries into their differences. Entries existing
+	// on both sides are represented with the same nodes in Left and Right.
+	Left map[any]Node
+	// Right maps keys of right entries into their differences.
+	Right map[any]Node
+}
+
+type values struct {
+	left  reflect.Value
+	right reflect.Value
+}
+
+// Values to implement Node.
+func (v values) Values() (left, right reflect.Value) {
+	return v.left, v.right
+}
+
+func (values) isNode() {}
+
+// exportDiff builds an exported difference tree from the internal one.
+func exportDiff(l, r reflect.Value, d diff.Diff) Node {
+	vals := values{left: l, right: r}
+
+	switch v := d.(type) {
+	case nil:
+		return nil
+	case *diff.Type:
+		return &TypeNode{values: vals}
+	case *diff.Value, *diff.Text:
+		return &ValueNode{values: vals}
+	case *diff.Missing:
+		return &MissingNode{values: vals}
+	case *diff.Fields:
+		ls, rs := deref(l), deref(r)
+		res := &FieldsNode{
+			values: vals,
+			Fields: make(map[string]Node, len(v.Fields)),
+		}
+		if _, ok := protoMessageOf(ls); ok {
+			for name, fd := range v.Fields {
+				res.Fields[name] = exportDiff(protoStructField(ls, name), protoStructField(rs, name), fd)
+			}
+
+			return res
+		}
+
+		for name, fd := range v.Fields {
+			f, _ := ls.Type().FieldByName(name)
+			res.Fields[name] = exportDiff(getField(ls, f.Index[0]), getField(rs, f.Index[0]), fd)
+		}
+
+		return res
+	case *diff.Indices:
+		ls, rs := deref(l), deref(r)
+		res := &IndicesNode{
+			values: vals,
+			Left:   make(map[int]Node, len(v.Left)),
+			Right:  make(map[int]Node, len(v.Right)),
+		}
+		for i, id := range v.Left {
+			res.Left[i] = exportDiff(ls.Index(i), reflect.Value{}, id)
+		}
+		for i, id := range v.Right {
+			res.Right[i] = exportDiff(reflect.Value{}, rs.Index(i), id)
+		}
+
+		return res
+	case *diff.Keys:
+		ls, rs := deref(l), deref(r)
+		res := &KeysNode{
+			values: vals,
+			Left:   make(map[any]Node, len(v.Left)),
+			Right:  make(map[any]Node, len(v.Right)),
+		}
+		for _, k := range mapKeys(ls, rs) {
+			key := k.Interface()
+			if kd, ok := v.Left[key]; ok {
+				n := exportDiff(ls.MapIndex(k), rs.MapIndex(k), kd)
+				res.Left[key] = n
+				if _, ok := v.Right[key]; ok {
+					res.Right[key] = n
+				}
+				continue
+			}
+
+			if kd, ok := v.Right[key]; ok {
+				res.Right[key] = exportDiff(ls.MapIndex(k), rs.MapIndex(k), kd)
+			}
+		}
+
+		return res
+	default:
+		panic("unsupported difference type " + reflect.TypeOf(d).String())
+	}
+}
+
+// mapKeys returns keys of both maps. Keys are kept as reflect.Value as a nil
+// interface key cannot be reflected back from any.
+func mapKeys(l, r reflect.Value) []reflect.Value {
+	keys := l.MapKeys()
+	for _, key := range r.MapKeys() {
+		if !l.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// deref dereferences pointers and interfaces down to the concrete value.
+func deref(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	return v
+}
@@ -0,0 +1,90 @@
+package deepequal_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sirkon/deepequal"
+)
+
+func TestDiff(t *testing.T) {
+	type item struct {
+		Name  string
+		Price int
+	}
+	type order struct {
+		ID    string
+		Items []item
+		Tags  map[string]int
+		Note  any
+	}
+
+	want := &order{
+		ID:    "1",
+		Items: []item{{Name: "a", Price: 1}, {Name: "b", Price: 2}},
+		Tags:  map[string]int{"x": 1, "y": 2},
+		Note:  "note",
+	}
+	got := &order{
+		ID:    "1",
+		Items: []item{{Name: "a", Price: 1}, {Name: "c", Price: 3}},
+		Tags:  map[string]int{"x": 2, "z": 3},
+		Note:  1,
+	}
+
+	if d := deepequal.Diff(want, want); d != nil {
+		t.Fatalf("no difference expected, got %#v", d)
+	}
+
+	root, ok := deepequal.Diff(want, got).(*deepequal.FieldsNode)
+	if !ok {
+		t.Fatalf("fields difference expected")
+	}
+	if l, r := root.Values(); l.Interface() != want || r.Interface() != got {
+		t.Error("unexpected root values")
+	}
+	if len(root.Fields) != 3 {
+		t.Errorf("3 different fields expected, got %d", len(root.Fields))
+	}
+
+	items, ok := root.Fields["Items"].(*deepequal.IndicesNode)
+	if !ok {
+		t.Fatalf("indices difference expected for Items")
+	}
+	missing, ok := items.Left[1].(*deepequal.MissingNode)
+	if !ok {
+		t.Fatalf("missing element expected for Items[1] on the left")
+	}
+	if l, r := missing.Values(); !reflect.DeepEqual(l.Interface(), item{Name: "b", Price: 2}) || r.IsValid() {
+		t.Errorf("unexpected values of Items[1] on the left")
+	}
+	if _, ok := items.Right[1].(*deepequal.MissingNode); !ok {
+		t.Errorf("missing element expected for Items[1] on the right")
+	}
+
+	tags, ok := root.Fields["Tags"].(*deepequal.KeysNode)
+	if !ok {
+		t.Fatalf("keys difference expected for Tags")
+	}
+	x, ok := tags.Left["x"].(*deepequal.ValueNode)
+	if !ok || tags.Right["x"] != x {
+		t.Fatalf("the same value difference expected for Tags[x] on both sides")
+	}
+	if l, r := x.Values(); l.Int() != 1 || r.Int() != 2 {
+		t.Errorf("unexpected values of Tags[x]")
+	}
+	if _, ok := tags.Left["y"].(*deepequal.MissingNode); !ok {
+		t.Errorf("missing entry expected for Tags[y] on the left")
+	}
+	if _, ok := tags.Right["z"].(*deepequal.MissingNode); !ok {
+		t.Errorf("missing entry expected for Tags[z] on the right")
+	}
+
+	if _, ok := root.Fields["Note"].(*deepequal.TypeNode); !ok {
+		t.Errorf("type difference expected for Note")
+	}
+
+	if _, ok := deepequal.Diff(nil, 1).(*deepequal.ValueNode); !ok {
+		t.Errorf("value difference expected for nil and non-nil values")
+	}
+}
@@ -0,0 +1,315 @@
+package deepequal
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sirkon/deepequal/internal/diff"
+)
+
+// Unified makes SideBySideWith to render a single Go-like value tree with -/+
+// prefixed lines at differing nodes instead of two columns.
+func Unified() Option {
+	return func(o *options) {
+		o.unified = true
+	}
+}
+
+// unifiedPrinter renders a difference as a single value tree. Equal parts
+// are shared, differing nodes are shown as removed (expected) and added (actual) lines.
+type unifiedPrinter struct {
+	opts  *options
+	color bool
+	buf   bytes.Buffer
+}
+
+// unifiedItem is a value being printed with its surroundings.
+type unifiedItem struct {
+	indent  string
+	label   string
+	suffix  string
+	path    valuePath
+	isProto bool
+}
+
+func renderUnified(l, r reflect.Value, opts *options) string {
+	u := &unifiedPrinter{
+		opts:  opts,
+		color: opts.colored(),
+	}
+	u.header(formatGreen, "--- Expected")
+	u.header(formatRed, "+++ Actual")
+
+	d := difference(l, r, false, walkSet{}, opts, opts.pathFor(l))
+	u.print(unifiedItem{path: opts.pathFor(l)}, l, r, d, true)
+
+	start := "\r"
+	if !u.color {
+		start = "\n"
+	}
+	return start + u.buf.String()
+}
+
+func (u *unifiedPrinter) print(it unifiedItem, l, r reflect.Value, d diff.Diff, showType bool) {
+	if u.opts.protoText && d != nil && l.IsValid() && l.CanInterface() {
+		// Messages rendered in the text format differ as a whole.
+		if _, ok := getProtoMessage(l.Interface()); ok {
+			u.value('-', it, l, showType)
+			u.value('+', it, r, showType)
+			return
+		}
+	}
+
+	switch v := d.(type) {
+	case nil:
+		u.value(' ', it, l, showType)
+	case *diff.Fields:
+		u.fields(it, l, r, v)
+	case *diff.Indices:
+		u.indices(it, l, r, v)
+	case *diff.Keys:
+		u.keys(it, l, r, v)
+	default:
+		u.value('-', it, l, showType)
+		u.value('+', it, r, showType)
+	}
+}
+
+func (u *unifiedPrinter) fields(it unifiedItem, l, r reflect.Value, d *diff.Fields) {
+	prefix, l, isProto := u.open(it, l)
+	r, _, _ = derefPrinted(r)
+	it.isProto = it.isProto || isProto
+
+	t := l.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if it.isProto && !f.IsExported() {
+			continue
+		}
+
+		fpath := it.path.field(f.Name)
+		if name, ok := protoFieldName(f); ok && it.isProto {
+			fpath = it.path.field(name)
+		}
+		field := unifiedItem{
+			indent: prefix,
+			label:  f.Name + ": ",
+			suffix: ",",
+			path:   fpath,
+		}
+		if u.opts.ignoreField(f, fpath) {
+			field.suffix = ", // ignored"
+			u.value(' ', field, getField(l, i), false)
+			continue
+		}
+
+		u.print(field, getField(l, i), getField(r, i), structFieldDiff(d.Fields, f, getField(l, i), it.isProto), false)
+	}
+
+	if it.isProto {
+		u.extras(prefix, it.path, l, r, d)
+	}
+
+	u.close(it)
+}
+
+// extras prints extensions and unknown fields of protobuf messages.
+func (u *unifiedPrinter) extras(prefix string, path valuePath, l, r reflect.Value, d *diff.Fields) {
+	var keys []string
+	lx := map[string]protoExtra{}
+	for _, extra := range protoExtras(l) {
+		keys = append(keys, extra.key)
+		lx[extra.key] = extra
+	}
+	rx := map[string]protoExtra{}
+	for _, extra := range protoExtras(r) {
+		if _, ok := lx[extra.key]; !ok {
+			keys = append(keys, extra.key)
+		}
+		rx[extra.key] = extra
+	}
+
+	for _, key := range keys {
+		lv, lok := lx[key]
+		rv, rok := rx[key]
+		entry := unifiedItem{
+			indent: prefix,
+			label:  key + ": ",
+			suffix: ",",
+			path:   path.field(key),
+		}
+		if lv.unknown || rv.unknown {
+			entry.suffix = ", // unknown"
+		}
+
+		switch {
+		case !rok:
+			u.value('-', entry, lv.value, false)
+		case !lok:
+			u.value('+', entry, rv.value, false)
+		default:
+			u.print(entry, lv.value, rv.value, d.Fields[key], false)
+		}
+	}
+}
+
+func (u *unifiedPrinter) indices(it unifiedItem, l, r reflect.Value, d *diff.Indices) {
+	prefix, l, _ := u.open(it, l)
+	r, _, _ = derefPrinted(r)
+
+	elem := func(i int) unifiedItem {
+		return unifiedItem{
+			indent: prefix,
+			suffix: ",",
+			path:   it.path.index(i),
+		}
+	}
+
+	var i, j int
+	for i < l.Len() || j < r.Len() {
+		switch {
+		case i < l.Len() && d.Left[i] != nil:
+			u.value('-', elem(i), l.Index(i), false)
+			i++
+		case j < r.Len() && d.Right[j] != nil:
+			u.value('+', elem(j), r.Index(j), false)
+			j++
+		case i < l.Len() && j < r.Len():
+			u.value(' ', elem(i), l.Index(i), false)
+			i++
+			j++
+		case i < l.Len():
+			u.value('-', elem(i), l.Index(i), false)
+			i++
+		default:
+			u.value('+', elem(j), r.Index(j), false)
+			j++
+		}
+	}
+
+	u.close(it)
+}
+
+func (u *unifiedPrinter) keys(it unifiedItem, l, r reflect.Value, d *diff.Keys) {
+	prefix, l, _ := u.open(it, l)
+	r, _, _ = derefPrinted(r)
+
+	keys := l.MapKeys()
+	for _, key := range r.MapKeys() {
+		if !l.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return compareReflectValues(keys[i], keys[j])
+	})
+
+	for _, key := range keys {
+		entry := unifiedItem{
+			indent: prefix,
+			label:  u.render(prefix, key, it.path, false) + ": ",
+			suffix: ",",
+			path:   it.path.key(key),
+		}
+
+		lv := l.MapIndex(key)
+		rv := r.MapIndex(key)
+		switch {
+		case !rv.IsValid():
+			u.value('-', entry, lv, false)
+		case !lv.IsValid():
+			u.value('+', entry, rv, false)
+		default:
+			u.print(entry, lv, rv, d.Left[key.Interface()], false)
+		}
+	}
+
+	u.close(it)
+}
+
+// open prints an opening line of a composite value and returns an indent
+// of its items, the dereferenced value and whether it is a protobuf message.
+func (u *unifiedPrinter) open(it unifiedItem, v reflect.Value) (string, reflect.Value, bool) {
+	v, refs, isProto := derefPrinted(v)
+	u.line(' ', it.indent+it.label+refs+v.Type().String()+"{")
+
+	return it.indent + "  ", v, isProto
+}
+
+func (u *unifiedPrinter) close(it unifiedItem) {
+	u.line(' ', it.indent+"}"+it.suffix)
+}
+
+// value prints the whole value with the given mark.
+func (u *unifiedPrinter) value(mark byte, it unifiedItem, v reflect.Value, showType bool) {
+	lines := strings.Split(u.render(it.indent, v, it.path, showType), "\n")
+	lines[0] = it.label + lines[0]
+	lines[len(lines)-1] += it.suffix
+
+	for i, line := range lines {
+		if i == 0 {
+			line = it.indent + line
+		}
+		u.line(mark, line)
+	}
+}
+
+// render renders the value with the value printer with no highlighting.
+func (u *unifiedPrinter) render(offset string, v reflect.Value, path valuePath, showType bool) string {
+	p := &printer{
+		buf:  &bytes.Buffer{},
+		opts: u.opts,
+	}
+	p.printValue(offset, v, path, nil, false, showType, map[uintptr]struct{}{})
+
+	return strings.ReplaceAll(p.buf.String(), "\r", "")
+}
+
+func (u *unifiedPrinter) line(mark byte, text string) {
+	var color string
+	switch mark {
+	case '-':
+		color = formatGreen
+	case '+':
+		color = formatRed
+	}
+
+	u.header(color, string(mark)+" "+text)
+}
+
+// header writes a line with no mark.
+func (u *unifiedPrinter) header(color string, text string) {
+	if u.color && color != "" {
+		u.buf.WriteString(color + text + formatReset)
+	} else {
+		u.buf.WriteString(text)
+	}
+
+	if u.color {
+		u.buf.WriteString("\n\r")
+	} else {
+		u.buf.WriteByte('\n')
+	}
+}
+
+// derefPrinted dereferences pointers and interfaces the way the value printer does
+// and returns their textual prefix.
+func derefPrinted(v reflect.Value) (reflect.Value, string, bool) {
+	var refs string
+	var isProto bool
+	for {
+		switch v.Kind() {
+		case reflect.Pointer:
+			_, isProto = v.Interface().(proto.Message)
+			refs += "&"
+			v = v.Elem()
+		case reflect.Interface:
+			v = v.Elem()
+		default:
+			return v, refs, isProto
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package deepequal_test
+
+import (
+	"testing"
+
+	"github.com/sirkon/deepequal"
+	"github.com/sirkon/deepequal/internal/testdata"
+)
+
+func TestUnified(t *testing.T) {
+	type item struct {
+		Name string
+	}
+	type sample struct {
+		Name  string
+		Items []item
+		Tags  map[string]int
+		Proto *testdata.Sample
+		Any   any
+	}
+
+	var rec recordingTesting
+	deepequal.SideBySideWith(
+		&rec,
+		"unified",
+		sample{
+			Name:  "a",
+			Items: []item{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+			Tags:  map[string]int{"x": 1, "y": 2},
+			Proto: &testdata.Sample{Str: "s", Sub: &testdata.Sub{Val: 1}},
+			Any:   1,
+		},
+		sample{
+			Name:  "b",
+			Items: []item{{Name: "a"}, {Name: "c"}, {Name: "d"}},
+			Tags:  map[string]int{"x": 2, "z": 2},
+			Proto: &testdata.Sample{Str: "s", Sub: &testdata.Sub{Val: 2}},
+			Any:   "1",
+		},
+		deepequal.Unified(),
+		deepequal.Colors(deepequal.ColorNever),
+	)
+
+	const want = `
+--- Expected
++++ Actual
+  deepequal_test.sample{
+-   Name: "a",
++   Name: "b",
+    Items: []deepequal_test.item{
+      deepequal_test.item{
+        Name: "a",
+      },
+-     deepequal_test.item{
+-       Name: "b",
+-     },
+      deepequal_test.item{
+        Name: "c",
+      },
++     deepequal_test.item{
++       Name: "d",
++     },
+    },
+    Tags: map[string]int{
+-     "x": 1,
++     "x": 2,
+-     "y": 2,
++     "z": 2,
+    },
+    Proto: &testdata.Sample{
+      Str: "s",
+      Sub: &testdata.Sub{
+-       Val: 1,
++       Val: 2,
+      },
+    },
+-   Any: int(1),
++   Any: "1",
+  }
+`
+	if got := rec.logs.String(); got != want {
+		t.Errorf("unexpected output\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,130 @@
+package deepequal
+
+import (
+	"reflect"
+)
+
+// IgnoreOrder makes all slices to be compared as multisets, i.e. regardless of elements order.
+// This includes repeated fields of protobuf messages.
+func IgnoreOrder() Option {
+	return func(o *options) {
+		o.unorderedAll = true
+	}
+}
+
+// IgnoreOrderAt makes slices at given paths to be compared regardless of elements order.
+// See IgnorePaths for the path expressions syntax. Path steps of protobuf message fields
+// are their proto names: Order.items[*].tags rather than Order.Items[*].Tags.
+func IgnoreOrderAt(exprs ...string) Option {
+	patterns := make([]pathPattern, len(exprs))
+	for i, expr := range exprs {
+		patterns[i] = mustParsePattern(expr)
+	}
+
+	return func(o *options) {
+		o.unorderedPaths = append(o.unorderedPaths, patterns...)
+	}
+}
+
+// IgnoreOrderOf makes slices of T to be compared regardless of elements order.
+func IgnoreOrderOf[T any]() Option {
+	return func(o *options) {
+		if o.unorderedTypes == nil {
+			o.unorderedTypes = map[reflect.Type]struct{}{}
+		}
+
+		o.unorderedTypes[typeOf[T]()] = struct{}{}
+	}
+}
+
+// unordered checks if a slice with given elements type at the given path must be
+// compared regardless of elements order.
+func (o *options) unordered(elem reflect.Type, path valuePath) bool {
+	if o.unorderedAll {
+		return true
+	}
+
+	if _, ok := o.unorderedTypes[elem]; ok {
+		return true
+	}
+
+	for _, pattern := range o.unorderedPaths {
+		if pattern.match(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchMultisets matches elements of two sequences of the given lengths regardless of their order.
+// Returns indices of elements having no pair on the left and on the right.
+//
+// Equality is not necessarily transitive, with float tolerances for instance, so the first found
+// pair may block a valid pairing of other elements. Elements are matched with the maximum bipartite
+// matching via augmenting paths therefore.
+func matchMultisets(n, m int, eq func(i, j int) bool) (left, right []int) {
+	// Comparisons are costly and every pair may be checked several times, so results are cached.
+	const (
+		unknown = iota
+		equal
+		different
+	)
+	cache := make([][]byte, n)
+	for i := range cache {
+		cache[i] = make([]byte, m)
+	}
+	pair := func(i, j int) bool {
+		if cache[i][j] == unknown {
+			cache[i][j] = different
+			if eq(i, j) {
+				cache[i][j] = equal
+			}
+		}
+
+		return cache[i][j] == equal
+	}
+
+	// matched[j] is an index of the left element paired with the right element j, or -1.
+	matched := make([]int, m)
+	for j := range matched {
+		matched[j] = -1
+	}
+
+	var seen []bool
+	var augment func(i int) bool
+	augment = func(i int) bool {
+		for j := 0; j < m; j++ {
+			if seen[j] || !pair(i, j) {
+				continue
+			}
+
+			seen[j] = true
+			if matched[j] < 0 || augment(matched[j]) {
+				matched[j] = i
+				return true
+			}
+		}
+
+		return false
+	}
+
+	paired := make([]bool, n)
+	for i := 0; i < n; i++ {
+		seen = make([]bool, m)
+		paired[i] = augment(i)
+	}
+
+	for i, ok := range paired {
+		if !ok {
+			left = append(left, i)
+		}
+	}
+	for j, i := range matched {
+		if i < 0 {
+			right = append(right, j)
+		}
+	}
+
+	return left, right
+}